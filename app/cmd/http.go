@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"github.com/kecci/goscription/internal/controller"
+	"github.com/kecci/goscription/internal/health"
 	"github.com/kecci/goscription/internal/http"
 	"github.com/kecci/goscription/internal/library"
 	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/internal/library/mailer"
+	"github.com/kecci/goscription/internal/library/password"
+	"github.com/kecci/goscription/internal/metrics"
+	"github.com/kecci/goscription/internal/outbound"
+	"github.com/kecci/goscription/internal/ratelimit"
 	"github.com/kecci/goscription/internal/repository"
+	"github.com/kecci/goscription/internal/scheduler"
 	"github.com/kecci/goscription/internal/service"
 	"github.com/kecci/goscription/utility"
 	"github.com/spf13/cobra"
@@ -30,10 +37,18 @@ func inject() fx.Option {
 			library.NewConfig,
 			utility.NewTimeOutContext,
 			db.NewDB,
+			password.NewHasher,
+			mailer.NewMailer,
 		),
+		fx.Invoke(library.InitLogger, library.InitTracing),
+		health.Module,
+		metrics.Module,
+		ratelimit.Module,
 		repository.Module,
+		outbound.Module,
 		service.Module,
 		controller.Module,
 		http.Module,
+		scheduler.Module,
 	)
 }
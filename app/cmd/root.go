@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/kecci/goscription/internal/library"
+	"github.com/ory/viper"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -40,8 +44,32 @@ var (
 
 	mysqlCmd = &cobra.Command{
 		Use:   "mysql",
-		Short: "Show mysql connection name",
+		Short: "Show mysql connection url",
 		Run: func(cmd *cobra.Command, args []string) {
+			// several migrations hold more than one statement
+			cfg := mysql.NewConfig()
+			cfg.User = viper.GetString(`database.mysql.user`)
+			cfg.Passwd = viper.GetString(`database.mysql.pass`)
+			cfg.Net = "tcp"
+			cfg.Addr = net.JoinHostPort(viper.GetString(`database.mysql.host`), viper.GetString(`database.mysql.port`))
+			cfg.DBName = viper.GetString(`database.mysql.name`)
+			cfg.MultiStatements = true
+			fmt.Println("mysql://" + cfg.FormatDSN())
+		},
+	}
+
+	postgresCmd = &cobra.Command{
+		Use:   "postgres",
+		Short: "Show postgres connection url",
+		Run: func(cmd *cobra.Command, args []string) {
+			u := url.URL{
+				Scheme:   "postgres",
+				User:     url.UserPassword(viper.GetString(`database.postgres.user`), viper.GetString(`database.postgres.pass`)),
+				Host:     net.JoinHostPort(viper.GetString(`database.postgres.host`), viper.GetString(`database.postgres.port`)),
+				Path:     viper.GetString(`database.postgres.name`),
+				RawQuery: url.Values{"sslmode": {viper.GetString(`database.postgres.sslmode`)}}.Encode(),
+			}
+			fmt.Println(u.String())
 		},
 	}
 )
@@ -61,4 +89,5 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(projectCmd)
 	rootCmd.AddCommand(mysqlCmd)
+	rootCmd.AddCommand(postgresCmd)
 }
@@ -0,0 +1 @@
+ALTER TABLE `article` ADD FULLTEXT INDEX `article_fulltext` (`title`, `content`);
\ No newline at end of file
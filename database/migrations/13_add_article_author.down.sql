@@ -0,0 +1 @@
+ALTER TABLE `article` DROP FOREIGN KEY `article_author_fk`, DROP KEY `article_author`, DROP COLUMN `author_id`;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE `article` ADD COLUMN `author_id` int(11) DEFAULT NULL,
+  ADD KEY `article_author` (`author_id`),
+  ADD CONSTRAINT `article_author_fk` FOREIGN KEY (`author_id`) REFERENCES `user` (`id`);UPDATE `article` SET `author_id` = (
+  SELECT u.id FROM `user` u
+  LEFT JOIN `user_role` ur ON ur.user_id = u.id AND ur.role_id = (SELECT id FROM `role` WHERE `name` = 'admin')
+  WHERE u.deleted_at IS NULL
+  ORDER BY ur.user_id IS NULL, u.id
+  LIMIT 1
+) WHERE `author_id` IS NULL;
\ No newline at end of file
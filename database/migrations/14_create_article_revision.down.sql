@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `article_revision`;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `article_revision` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `article_id` int(11) NOT NULL,
+  `version` int(11) NOT NULL,
+  `title` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `content` longtext COLLATE utf8_unicode_ci NOT NULL,
+  `edited_by` int(11) DEFAULT NULL,
+  `edited_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `article_revision_version` (`article_id`, `version`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
\ No newline at end of file
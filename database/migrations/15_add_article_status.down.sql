@@ -0,0 +1 @@
+ALTER TABLE `article` DROP KEY `article_status_publish_at`, DROP COLUMN `publish_at`, DROP COLUMN `status`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `article` ADD COLUMN `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL DEFAULT 'draft',
+  ADD COLUMN `publish_at` datetime DEFAULT NULL,
+  ADD KEY `article_status_publish_at` (`status`, `publish_at`);UPDATE `article` SET `status` = 'published', `publish_at` = `created_at`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `article` DROP KEY `article_deleted_at`, DROP COLUMN `deleted_at`;
\ No newline at end of file
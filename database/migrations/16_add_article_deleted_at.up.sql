@@ -0,0 +1,2 @@
+ALTER TABLE `article` ADD COLUMN `deleted_at` datetime DEFAULT NULL,
+  ADD KEY `article_deleted_at` (`deleted_at`);
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `rate_limit_bucket` (
+  `key` char(64) NOT NULL,
+  `tokens` double NOT NULL,
+  `updated_at` datetime(6) NOT NULL,
+  `full_at` datetime(6) NOT NULL,
+  PRIMARY KEY (`key`),
+  KEY `rate_limit_bucket_full_at` (`full_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
\ No newline at end of file
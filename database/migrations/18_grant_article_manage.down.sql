@@ -0,0 +1,2 @@
+DELETE rp FROM `role_permission` rp JOIN `role` r ON r.id = rp.role_id JOIN `permission` p ON p.id = rp.permission_id
+  WHERE r.name = 'author' AND p.name = 'article:delete';DELETE rp FROM `role_permission` rp JOIN `permission` p ON p.id = rp.permission_id WHERE p.name = 'article:manage';DELETE FROM `permission` WHERE `name` = 'article:manage';
\ No newline at end of file
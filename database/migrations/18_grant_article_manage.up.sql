@@ -0,0 +1,4 @@
+INSERT IGNORE INTO `permission` (`name`) VALUES ('article:manage');INSERT IGNORE INTO `role_permission` (`role_id`, `permission_id`)
+  SELECT r.id, p.id FROM `role` r JOIN `permission` p
+  WHERE (r.name IN ('editor', 'admin') AND p.name = 'article:manage')
+     OR (r.name = 'author' AND p.name = 'article:delete');
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `article` DROP COLUMN `version`;
\ No newline at end of file
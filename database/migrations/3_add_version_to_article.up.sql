@@ -0,0 +1 @@
+ALTER TABLE `article` ADD COLUMN `version` int(11) NOT NULL DEFAULT 1 AFTER `content`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `user` MODIFY `password` varchar(255) COLLATE utf8_unicode_ci NOT NULL;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS `role` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `role_name` (`name`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;CREATE TABLE IF NOT EXISTS `permission` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `permission_name` (`name`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;CREATE TABLE IF NOT EXISTS `role_permission` (
+  `role_id` int(11) NOT NULL,
+  `permission_id` int(11) NOT NULL,
+  PRIMARY KEY (`role_id`, `permission_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;CREATE TABLE IF NOT EXISTS `user_role` (
+  `user_id` int(11) NOT NULL,
+  `role_id` int(11) NOT NULL,
+  PRIMARY KEY (`user_id`, `role_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;INSERT IGNORE INTO `role` (`name`) VALUES ('reader'), ('author'), ('editor'), ('admin');INSERT IGNORE INTO `permission` (`name`) VALUES ('article:create'), ('article:update'), ('article:delete'), ('user:read'), ('user:manage');INSERT IGNORE INTO `role_permission` (`role_id`, `permission_id`)
+  SELECT r.id, p.id FROM `role` r JOIN `permission` p
+  WHERE (r.name = 'author' AND p.name IN ('article:create', 'article:update'))
+     OR (r.name = 'editor' AND p.name IN ('article:create', 'article:update', 'article:delete'))
+     OR (r.name = 'admin');INSERT IGNORE INTO `user_role` (`user_id`, `role_id`)
+  SELECT u.id, r.id FROM `user` u JOIN `role` r WHERE r.name = 'reader';
\ No newline at end of file
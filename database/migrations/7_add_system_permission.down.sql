@@ -0,0 +1 @@
+DELETE rp FROM `role_permission` rp JOIN `permission` p ON p.id = rp.permission_id WHERE p.name = 'system:read';DELETE FROM `permission` WHERE `name` = 'system:read';
\ No newline at end of file
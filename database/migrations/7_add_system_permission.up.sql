@@ -0,0 +1,3 @@
+INSERT INTO `permission` (`name`) VALUES ('system:read');INSERT INTO `role_permission` (`role_id`, `permission_id`)
+  SELECT r.id, p.id FROM `role` r JOIN `permission` p
+  WHERE r.name = 'admin' AND p.name = 'system:read';
\ No newline at end of file
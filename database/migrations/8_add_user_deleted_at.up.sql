@@ -0,0 +1 @@
+ALTER TABLE `user` ADD COLUMN `deleted_at` datetime DEFAULT NULL;
\ No newline at end of file
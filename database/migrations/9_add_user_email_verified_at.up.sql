@@ -0,0 +1 @@
+ALTER TABLE `user` ADD COLUMN `email_verified_at` datetime DEFAULT NULL;UPDATE `user` SET `email_verified_at` = NOW();
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "address";
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "address" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "address_title" varchar(45) NOT NULL,
+  "address_full" text NOT NULL,
+  "district_name" varchar(45) NOT NULL DEFAULT '',
+  "subdistrict_name" varchar(45) NOT NULL DEFAULT '',
+  "zip_code" varchar(5) NOT NULL DEFAULT '',
+  "primary" boolean NOT NULL DEFAULT false,
+  "created_by" varchar(45) NOT NULL DEFAULT '',
+  "created_at" timestamptz DEFAULT NULL,
+  "updated_by" varchar(45) NOT NULL DEFAULT '',
+  "updated_at" timestamptz DEFAULT NULL,
+  PRIMARY KEY ("id")
+);CREATE INDEX IF NOT EXISTS "address_user_id" ON "address" ("user_id");
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "address_user_primary";
\ No newline at end of file
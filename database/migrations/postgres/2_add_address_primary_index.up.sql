@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS "address_user_primary" ON "address" ("user_id") WHERE "primary";
\ No newline at end of file
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type accountController struct {
+	AccountService service.AccountService
+}
+
+// InitAccountController will initialize the account's HTTP controller
+func InitAccountController(e *echo.Echo, as service.AccountService) {
+	controller := &accountController{
+		AccountService: as,
+	}
+	e.POST("/auth/verify-email", controller.VerifyEmail)
+	e.POST("/auth/verify-email/resend", controller.ResendVerification)
+	e.POST("/auth/forgot-password", controller.ForgotPassword)
+	e.POST("/auth/reset-password", controller.ResetPassword)
+}
+
+// TokenRequest verify-email body request
+type TokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// EmailRequest resend and forgot-password body request
+type EmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest reset-password body request
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Redeem the token mailed on sign up, tokens are single-use
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param token body TokenRequest true "Verification token"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/verify-email [post]
+func (a *accountController) VerifyEmail(c echo.Context) error {
+	var tokenRequest TokenRequest
+	err := c.Bind(&tokenRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&tokenRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.AccountService.VerifyEmail(ctx, tokenRequest.Token)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification mail
+// @Description Mail a new verification link, the previous ones stop working. The answer is the same whether the email is registered or not.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param email body EmailRequest true "Email"
+// @Success 202
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/verify-email/resend [post]
+func (a *accountController) ResendVerification(c echo.Context) error {
+	var emailRequest EmailRequest
+	err := c.Bind(&emailRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&emailRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.AccountService.ResendVerification(ctx, emailRequest.Email)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ForgotPassword godoc
+// @Summary Ask for a password reset
+// @Description Mail a password reset link. The answer is the same whether the email is registered or not.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param email body EmailRequest true "Email"
+// @Success 202
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/forgot-password [post]
+func (a *accountController) ForgotPassword(c echo.Context) error {
+	var emailRequest EmailRequest
+	err := c.Bind(&emailRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&emailRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.AccountService.ForgotPassword(ctx, emailRequest.Email)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Reset a forgotten password
+// @Description Set a new password with the token of a reset mail, every session of the User is signed out
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param reset body ResetPasswordRequest true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/reset-password [post]
+func (a *accountController) ResetPassword(c echo.Context) error {
+	var resetRequest ResetPasswordRequest
+	err := c.Bind(&resetRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&resetRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.AccountService.ResetPassword(ctx, resetRequest.Token, resetRequest.Password)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,11 +1,12 @@
 package controller
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/kecci/goscription/internal/service"
-	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
 	"github.com/labstack/echo/v4"
 )
 
@@ -13,56 +14,260 @@ type addressController struct {
 	addressService service.AddressService
 }
 
-func NewAddressController(e *echo.Echo, addressService service.AddressService) {
+// InitAddressController will initialize the address's HTTP controller
+func InitAddressController(e *echo.Echo, addressService service.AddressService) {
 	controller := &addressController{
 		addressService: addressService,
 	}
 
-	e.GET("/address", controller.GetAddressAll)
-	e.POST("/address", controller.Insert)
+	e.GET("/users/:id/addresses", controller.Fetch, requirePermission())
+	e.POST("/users/:id/addresses", controller.Store, requirePermission())
+	e.GET("/users/:id/addresses/:addressId", controller.GetByID, requirePermission())
+	e.PUT("/users/:id/addresses/:addressId", controller.Update, requirePermission())
+	e.DELETE("/users/:id/addresses/:addressId", controller.Delete, requirePermission())
 }
 
-// GetAddressAll godoc
-// @Summary Show a Address
-// @Description get address
-// @Tags address
+// AddressRequest address body request
+type AddressRequest struct {
+	AddressTitle    string `json:"address_title" validate:"required,max=45"`
+	AddressFull     string `json:"address_full" validate:"required"`
+	DistrictName    string `json:"district_name" validate:"max=45"`
+	SubdistrictName string `json:"subdistrict_name" validate:"max=45"`
+	ZipCode         string `json:"zip_code" validate:"omitempty,zipcode"`
+	Primary         bool   `json:"primary"`
+}
+
+// addressPath reads the user id and, when the route has one, the address id
+func addressPath(c echo.Context) (userID, id int64, err error) {
+	userID, err = strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, utility.ErrBadParamInput.Wrap(err)
+	}
+	if c.Param("addressId") == "" {
+		return userID, 0, nil
+	}
+	id, err = strconv.ParseInt(c.Param("addressId"), 10, 64)
+	if err != nil {
+		return 0, 0, utility.ErrBadParamInput.Wrap(err)
+	}
+	return userID, id, nil
+}
+
+// bindAddress reads and validates the address body of a request
+func bindAddress(c echo.Context) (AddressRequest, error) {
+	var req AddressRequest
+	if err := c.Bind(&req); err != nil {
+		return AddressRequest{}, utility.ErrUnprocessableEntity.Wrap(err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return AddressRequest{}, err
+	}
+	return req, nil
+}
+
+// Fetch godoc
+// @Summary List the addresses of a user
+// @Description List the addresses of a user, newest first
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Success 200 {array} models.Address
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/addresses [get]
+func (a *addressController) Fetch(c echo.Context) error {
+	userID, _, err := addressPath(c)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	num, _ := strconv.Atoi(c.QueryParam("num"))
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	addresses, nextCursor, err := a.addressService.Fetch(ctx, userID, cursor, int64(num))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, addresses)
+}
+
+// GetByID godoc
+// @Summary Show an address of a user
+// @Description Show an address of a user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param addressId path int true "Address ID"
+// @Success 200 {object} models.Address
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/addresses/{addressId} [get]
+func (a *addressController) GetByID(c echo.Context) error {
+	userID, id, err := addressPath(c)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	address, err := a.addressService.GetByID(ctx, userID, id)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+	return c.JSON(http.StatusOK, address)
+}
+
+// Store godoc
+// @Summary Create an address for a user
+// @Description Create an address for a user, the first one becomes the primary address
+// @Tags addresses
 // @Accept json
 // @Produce json
-// @Header 200 {string} models.BaseResponse
-// @Failure 400 {object} models.BaseResponse
-// @Failure 404 {object} models.BaseResponse
-// @Failure 500 {object} models.BaseResponse
-// @Router /address [get]
-func (a *addressController) GetAddressAll(c echo.Context) error {
-	addresses, err := a.addressService.GetAddressAll()
+// @Param id path int true "User ID"
+// @Param address body AddressRequest true "Address Body"
+// @Success 201 {object} models.Address
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/addresses [post]
+func (a *addressController) Store(c echo.Context) error {
+	userID, _, err := addressPath(c)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	req, err := bindAddress(c)
 	if err != nil {
-		fmt.Println(err.Error())
-		return c.JSON(http.StatusInternalServerError, models.BaseResponse{Code: "FAILED", Message: "FAILED", Error: []string{err.Error()}})
+		return utility.RenderError(c, err)
 	}
-	return c.JSON(http.StatusOK, models.BaseResponse{Code: "SUCCESS", Message: "SUCCESS", Data: addresses})
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	address, err := a.addressService.Store(ctx, service.AddressParam{
+		UserID:          userID,
+		AddressTitle:    req.AddressTitle,
+		AddressFull:     req.AddressFull,
+		DistrictName:    req.DistrictName,
+		SubdistrictName: req.SubdistrictName,
+		ZipCode:         req.ZipCode,
+		Primary:         req.Primary,
+	})
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+	return c.JSON(http.StatusCreated, address)
 }
 
-// Insert godoc
-// @Summary Show a Address
-// @Description insert address
-// @Tags address
+// Update godoc
+// @Summary Update an address of a user
+// @Description Update an address of a user, making it primary demotes the previous primary address
+// @Tags addresses
 // @Accept json
 // @Produce json
-// @Param address body models.Address true "address"
-// @Header 200 {string} models.BaseResponse
-// @Failure 400 {object} models.BaseResponse
-// @Failure 404 {object} models.BaseResponse
-// @Failure 500 {object} models.BaseResponse
-// @Router /address [post]
-func (a *addressController) Insert(c echo.Context) error {
-	var address models.Address
-	if err := c.Bind(&address); err != nil {
-		return c.JSON(http.StatusBadRequest, models.BaseResponse{Code: "FAILED", Message: "FAILED", Error: []string{err.Error()}})
-	}
-	err := a.addressService.Insert(address)
+// @Param id path int true "User ID"
+// @Param addressId path int true "Address ID"
+// @Param address body AddressRequest true "Address Body"
+// @Success 200 {object} models.Address
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/addresses/{addressId} [put]
+func (a *addressController) Update(c echo.Context) error {
+	userID, id, err := addressPath(c)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	req, err := bindAddress(c)
 	if err != nil {
-		fmt.Println(err.Error())
-		return c.JSON(http.StatusInternalServerError, models.BaseResponse{Code: "FAILED", Message: "FAILED", Error: []string{err.Error()}})
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	address, err := a.addressService.Update(ctx, service.AddressParam{
+		ID:              id,
+		UserID:          userID,
+		AddressTitle:    req.AddressTitle,
+		AddressFull:     req.AddressFull,
+		DistrictName:    req.DistrictName,
+		SubdistrictName: req.SubdistrictName,
+		ZipCode:         req.ZipCode,
+		Primary:         req.Primary,
+	})
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+	return c.JSON(http.StatusOK, address)
+}
+
+// Delete godoc
+// @Summary Delete an address of a user
+// @Description Delete an address of a user, the oldest remaining address takes over as primary
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param addressId path int true "Address ID"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/addresses/{addressId} [delete]
+func (a *addressController) Delete(c echo.Context) error {
+	userID, id, err := addressPath(c)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err = a.addressService.Delete(ctx, userID, id); err != nil {
+		return utility.RenderError(c, err)
 	}
-	return c.JSON(http.StatusOK, models.BaseResponse{Code: "SUCCESS", Message: "SUCCESS", Data: true})
+	return c.NoContent(http.StatusNoContent)
 }
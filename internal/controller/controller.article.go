@@ -2,14 +2,23 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
 type articleController struct {
 	AService service.ArticleService
 }
@@ -20,14 +29,28 @@ func InitArticleController(e *echo.Echo, us service.ArticleService) {
 		AService: us,
 	}
 	e.GET("/articles", controller.FetchArticle)
-	e.POST("/articles", controller.Store)
+	e.GET("/articles/search", controller.SearchArticle)
+	e.GET("/articles/trash", controller.Trash, requirePermission())
+	e.GET("/users/:id/articles", controller.FetchByAuthor)
+	e.POST("/articles", controller.Store, requirePermission(models.PermissionArticleCreate))
 	e.GET("/articles/:id", controller.GetByID)
-	e.DELETE("/articles/:id", controller.Delete)
+	e.PUT("/articles/:id", controller.Update, requirePermission(models.PermissionArticleUpdate))
+	e.DELETE("/articles/:id", controller.Delete, requirePermission(models.PermissionArticleDelete))
+	e.POST("/articles/:id/restore", controller.RestoreTrashed, requirePermission(models.PermissionArticleDelete))
+	e.GET("/articles/:id/revisions", controller.Revisions)
+	e.GET("/articles/:id/revisions/diff", controller.Diff)
+	e.POST("/articles/:id/revisions/:rev/restore", controller.Restore, requirePermission(models.PermissionArticleUpdate))
 }
 
-// ResponseError will hold the response error structs
-type ResponseError struct {
-	Message string `json:"message"`
+// articleETag builds the entity tag of an article out of its version
+func articleETag(ar models.Article) string {
+	return fmt.Sprintf(`"%d"`, ar.Version)
+}
+
+// parseArticleETag reads the article version back out of an If-Match value
+func parseArticleETag(tag string) (int64, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	return strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
 }
 
 // FetchArticle godoc
@@ -38,10 +61,13 @@ type ResponseError struct {
 // @Produce  json
 // @Param num query string true "num"
 // @Param cursor query string true "cursor"
+// @Param tag query []string false "keep the articles carrying every given tag" collectionFormat(multi)
+// @Param status query string false "editors only, published by default" Enums(draft, scheduled, published, archived)
 // @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
 // @Router /articles [get]
 func (a *articleController) FetchArticle(c echo.Context) error {
 	numS := c.QueryParam("num")
@@ -52,9 +78,84 @@ func (a *articleController) FetchArticle(c echo.Context) error {
 		ctx = context.Background()
 	}
 
-	listAr, nextCursor, err := a.AService.Fetch(ctx, cursor, int64(num))
+	filter := models.ArticleFilter{Tags: c.QueryParams()["tag"], Status: c.QueryParam("status")}
+	listAr, nextCursor, err := a.AService.Fetch(ctx, filter, cursor, int64(num))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// FetchByAuthor godoc
+// @Summary Show the Articles of a User
+// @Description get the articles written by a user, newest first. Other callers than the author, and the editors, only see the published ones.
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Param tag query []string false "keep the articles carrying every given tag" collectionFormat(multi)
+// @Param status query string false "every status by default for the author" Enums(draft, scheduled, published, archived)
+// @Success 200 {array} models.Article
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /users/{id}/articles [get]
+func (a *articleController) FetchByAuthor(c echo.Context) error {
+	authorID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	filter := models.ArticleFilter{Tags: c.QueryParams()["tag"], AuthorID: authorID, Status: c.QueryParam("status")}
+	listAr, nextCursor, err := a.AService.Fetch(ctx, filter, cursor, int64(num))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// SearchArticle godoc
+// @Summary Search Articles
+// @Description Full-text search on the title and the content of the articles, the most relevant first. Matched terms are wrapped in <mark> in the HTML escaped highlights.
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param q query string true "search query"
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Success 200 {array} models.ArticleSearchResult
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /articles/search [get]
+func (a *articleController) SearchArticle(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	listAr, nextCursor, err := a.AService.Search(ctx, c.QueryParam("q"), cursor, int64(num))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
 	c.Response().Header().Set(`X-Cursor`, nextCursor)
@@ -69,15 +170,16 @@ func (a *articleController) FetchArticle(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Article ID"
-// @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Success 200 {object} models.Article
+// @Header 200 {string} ETag "Article version, send it back as If-Match when updating"
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
 // @Router /articles/{id} [get]
 func (a *articleController) GetByID(c echo.Context) error {
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
 	}
 
 	ctx := c.Request().Context()
@@ -88,16 +190,22 @@ func (a *articleController) GetByID(c echo.Context) error {
 	id := int64(idP)
 	art, err := a.AService.GetByID(ctx, id)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
+	c.Response().Header().Set(headerETag, articleETag(art))
 	return c.JSON(http.StatusOK, art)
 }
 
 // ArticleRequest article body request
 type ArticleRequest struct {
-	Title   string `json:"title" validate:"required"`
+	Title   string `json:"title" validate:"required,max=45"`
 	Content string `json:"content" validate:"required"`
+	// Tags replaces the tags of the article, leave it out to keep them
+	Tags []string `json:"tags" validate:"max=10,dive,required,max=45"`
+	// Status defaults to draft on create and is kept as it is on update when left out
+	Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published archived"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 // Store godoc
@@ -107,21 +215,32 @@ type ArticleRequest struct {
 // @Accept  json
 // @Produce  json
 // @Param article body ArticleRequest true "Article Body"
-// @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Success 201 {object} models.Article
+// @Header 201 {string} ETag "Article version, send it back as If-Match when updating"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
 // @Router /articles [post]
 func (a *articleController) Store(c echo.Context) error {
 	var articleRequest ArticleRequest
 	err := c.Bind(&articleRequest)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&articleRequest); err != nil {
+		return utility.RenderError(c, err)
 	}
 
 	articleParam := service.ArticleParam{
-		Title:   articleRequest.Title,
-		Content: articleRequest.Content,
+		Title:     articleRequest.Title,
+		Content:   articleRequest.Content,
+		Tags:      articleRequest.Tags,
+		Status:    articleRequest.Status,
+		PublishAt: articleRequest.PublishAt,
 	}
 
 	ctx := c.Request().Context()
@@ -129,26 +248,30 @@ func (a *articleController) Store(c echo.Context) error {
 		ctx = context.Background()
 	}
 
-	err = a.AService.Store(ctx, articleParam)
+	art, err := a.AService.Store(ctx, articleParam)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, articleParam)
+	c.Response().Header().Set(headerETag, articleETag(art))
+	return c.JSON(http.StatusCreated, art)
 }
 
 // Delete godoc
-// @Summary Create an Article
-// @Description Create an Article
+// @Summary Delete an Article
+// @Description Move an Article to the trash, it can be restored until it is purged
 // @Tags articles
 // @Accept  json
 // @Produce  json
-// @Param id query int true "Article ID"
-// @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
-// @Router /articles [delete]
+// @Param id path int true "Article ID"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /articles/{id} [delete]
 func (a *articleController) Delete(c echo.Context) error {
 	ctx := c.Request().Context()
 	if ctx == nil {
@@ -157,13 +280,13 @@ func (a *articleController) Delete(c echo.Context) error {
 
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
 	}
 
 	id := int64(idP)
 	err = a.AService.Delete(ctx, id)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -171,44 +294,271 @@ func (a *articleController) Delete(c echo.Context) error {
 
 // Update godoc
 // @Summary Update an Article
-// @Description Update an Article
+// @Description Update an Article, guarded by the ETag previously returned for it
 // @Tags articles
 // @Accept  json
 // @Produce  json
 // @Param article body ArticleRequest true "Article Body"
 // @Param id path int true "Article ID"
-// @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Param If-Match header string true "ETag of the article being edited"
+// @Success 200 {object} models.Article
+// @Header 200 {string} ETag "New article version"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 412 {object} models.Problem
+// @Failure 428 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
 // @Router /articles/{id} [put]
 func (a *articleController) Update(c echo.Context) error {
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ifMatch := c.Request().Header.Get(headerIfMatch)
+	if ifMatch == "" {
+		return utility.RenderError(c, utility.ErrPreconditionRequired)
+	}
+
+	version, err := parseArticleETag(ifMatch)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
 	}
 
 	var ar ArticleRequest
 	err = c.Bind(&ar)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&ar); err != nil {
+		return utility.RenderError(c, err)
 	}
 
 	articleParam := service.ArticleParam{
-		ID:      int64(idP),
-		Title:   ar.Title,
-		Content: ar.Content,
+		ID:        int64(idP),
+		Title:     ar.Title,
+		Content:   ar.Content,
+		Version:   version,
+		Tags:      ar.Tags,
+		Status:    ar.Status,
+		PublishAt: ar.PublishAt,
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	art, err := a.AService.Update(ctx, articleParam)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(headerETag, articleETag(art))
+	return c.JSON(http.StatusOK, art)
+}
+
+// Revisions godoc
+// @Summary Show the Revisions of an Article
+// @Description get the past states of an article, newest first. Each one tells who replaced it and when.
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Success 200 {array} models.ArticleRevision
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /articles/{id}/revisions [get]
+func (a *articleController) Revisions(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	revisions, nextCursor, err := a.AService.Revisions(ctx, id, cursor, int64(num))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// Diff godoc
+// @Summary Compare two Versions of an Article
+// @Description line-based diff of the title and the content between two versions, either may be the current version
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Param from query int true "older version"
+// @Param to query int true "newer version"
+// @Success 200 {object} models.ArticleDiff
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /articles/{id}/revisions/diff [get]
+func (a *articleController) Diff(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+	from, err := strconv.ParseInt(c.QueryParam("from"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+	to, err := strconv.ParseInt(c.QueryParam("to"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	diff, err := a.AService.Diff(ctx, id, from, to)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+	return c.JSON(http.StatusOK, diff)
+}
+
+// Restore godoc
+// @Summary Restore a Revision of an Article
+// @Description bring back the title and the content of a past version, the replaced state is kept as a revision
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Param rev path int true "Version to restore"
+// @Param If-Match header string true "ETag of the article being replaced"
+// @Success 200 {object} models.Article
+// @Header 200 {string} ETag "New article version"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 412 {object} models.Problem
+// @Failure 428 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /articles/{id}/revisions/{rev}/restore [post]
+func (a *articleController) Restore(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+	rev, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ifMatch := c.Request().Header.Get(headerIfMatch)
+	if ifMatch == "" {
+		return utility.RenderError(c, utility.ErrPreconditionRequired)
 	}
 
+	version, err := parseArticleETag(ifMatch)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	art, err := a.AService.Restore(ctx, id, rev, version)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(headerETag, articleETag(art))
+	return c.JSON(http.StatusOK, art)
+}
+
+// Trash godoc
+// @Summary Show the Trash
+// @Description get the trashed articles of the caller, editors get every trashed article
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Success 200 {array} models.Article
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /articles/trash [get]
+func (a *articleController) Trash(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	err = a.AService.Update(ctx, articleParam)
+	listAr, nextCursor, err := a.AService.Trash(ctx, cursor, int64(num))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, articleParam)
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// RestoreTrashed godoc
+// @Summary Restore an Article from the Trash
+// @Description take a trashed article back, as long as no other article took its title
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Success 200 {object} models.Article
+// @Header 200 {string} ETag "Article version"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /articles/{id}/restore [post]
+func (a *articleController) RestoreTrashed(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	art, err := a.AService.RestoreTrashed(ctx, id)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(headerETag, articleETag(art))
+	return c.JSON(http.StatusOK, art)
 }
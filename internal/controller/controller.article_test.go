@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/kecci/goscription/internal/controller"
+	httpServer "github.com/kecci/goscription/internal/http"
+	"github.com/kecci/goscription/internal/service"
 	"github.com/kecci/goscription/mocks"
 	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
@@ -20,6 +22,12 @@ import (
 	"github.com/bxcodec/faker"
 )
 
+// withPermissions authenticates req as a caller holding permissions
+func withPermissions(req *http.Request, permissions ...string) *http.Request {
+	user := models.AuthUser{ID: 1, Email: "caller@example.com", Permissions: permissions}
+	return req.WithContext(utility.WithAuthUser(req.Context(), user))
+}
+
 func TestFetch(t *testing.T) {
 	var mockArticle models.Article
 	err := faker.FakeData(&mockArticle)
@@ -29,7 +37,7 @@ func TestFetch(t *testing.T) {
 	mockListArticle = append(mockListArticle, mockArticle)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
+	mockUCase.On("Fetch", mock.Anything, models.ArticleFilter{}, cursor, int64(num)).Return(mockListArticle, "10", nil)
 
 	e := echo.New()
 	req, err := http.NewRequest(echo.GET, "/articles?num=1&cursor="+cursor, strings.NewReader(""))
@@ -47,11 +55,81 @@ func TestFetch(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
+func TestFetchByTags(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	filter := models.ArticleFilter{Tags: []string{"go", "echo"}}
+	mockUCase.On("Fetch", mock.Anything, filter, "", int64(0)).Return([]models.Article{}, "", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles?tag=go&tag=echo", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+
+	controller.InitArticleController(e, mockUCase)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockArticle := models.Article{ID: 3, Title: "Hello", Author: &models.ArticleAuthor{ID: 7, Name: "Author"}}
+	mockUCase.On("Fetch", mock.Anything, models.ArticleFilter{AuthorID: 7}, "", int64(0)).Return([]models.Article{mockArticle}, "3", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/users/7/articles", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+
+	controller.InitArticleController(e, mockUCase)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	if assert.Len(t, body, 1) {
+		assert.Equal(t, map[string]interface{}{"id": float64(7), "name": "Author"}, body[0]["author"])
+	}
+	mockUCase.AssertExpectations(t)
+}
+
+func TestSearch(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockResult := models.ArticleSearchResult{
+		Article:   models.Article{ID: 3, Title: "Clean Architecture"},
+		Score:     1.5,
+		Highlight: models.ArticleHighlight{Title: "<mark>Clean</mark> Architecture"},
+	}
+	mockUCase.On("Search", mock.Anything, "clean code", "", int64(5)).Return([]models.ArticleSearchResult{mockResult}, "next", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles/search?q=clean+code&num=5", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+
+	controller.InitArticleController(e, mockUCase)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "next", rec.Header().Get("X-Cursor"))
+	var body []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	if assert.Len(t, body, 1) {
+		assert.EqualValues(t, 3, body[0]["id"])
+		assert.EqualValues(t, 1.5, body[0]["score"])
+	}
+	mockUCase.AssertExpectations(t)
+}
+
 func TestFetchError(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", utility.ErrInternalServerError)
+	mockUCase.On("Fetch", mock.Anything, models.ArticleFilter{}, cursor, int64(num)).Return(nil, "", utility.ErrInternalServerError)
 
 	e := echo.New()
 	req, err := http.NewRequest(echo.GET, "/articles?num=1&cursor="+cursor, strings.NewReader(""))
@@ -88,6 +166,7 @@ func TestGetByID(t *testing.T) {
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"`+strconv.FormatInt(mockArticle.Version, 10)+`"`, rec.Header().Get("ETag"))
 	mockUCase.AssertExpectations(t)
 }
 
@@ -105,21 +184,61 @@ func TestStore(t *testing.T) {
 
 	j, err := json.Marshal(tempMockArticle)
 	assert.NoError(t, err)
-	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("service.ArticleParam")).Return(nil)
+	stored := mockArticle
+	stored.ID = 12
+	stored.Version = 1
+	stored.Author = &models.ArticleAuthor{ID: 7, Name: "Author"}
+	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("service.ArticleParam")).Return(stored, nil)
 
 	e := echo.New()
+	e.Validator = httpServer.NewValidator()
 	req, err := http.NewRequest(echo.POST, "/articles", strings.NewReader(string(j)))
 	assert.NoError(t, err)
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = withPermissions(req, models.PermissionArticleCreate)
 
 	rec := httptest.NewRecorder()
 	controller.InitArticleController(e, mockUCase)
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+	var res models.Article
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, int64(12), res.ID)
+	assert.Equal(t, stored.Author, res.Author)
 	mockUCase.AssertExpectations(t)
 }
 
+func TestStoreValidation(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	j, err := json.Marshal(controller.ArticleRequest{Title: strings.Repeat("a", 46)})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Validator = httpServer.NewValidator()
+	req, err := http.NewRequest(echo.POST, "/articles", strings.NewReader(string(j)))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = withPermissions(req, models.PermissionArticleCreate)
+
+	rec := httptest.NewRecorder()
+	controller.InitArticleController(e, mockUCase)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, []models.ErrorDetail{
+		{Field: "title", Rule: "max", Message: "must be at most 45 characters long"},
+		{Field: "content", Rule: "required", Message: "is required"},
+	}, problem.Errors)
+	mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
 func TestDelete(t *testing.T) {
 	var mockArticle models.Article
 	err := faker.FakeData(&mockArticle)
@@ -132,6 +251,7 @@ func TestDelete(t *testing.T) {
 	e := echo.New()
 	req, err := http.NewRequest(echo.DELETE, "/articles/"+strconv.Itoa(num), strings.NewReader(""))
 	assert.NoError(t, err)
+	req = withPermissions(req, models.PermissionArticleDelete)
 
 	rec := httptest.NewRecorder()
 	controller.InitArticleController(e, mockUCase)
@@ -140,3 +260,215 @@ func TestDelete(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
+
+func TestDeleteGuard(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	t.Run("anonymous", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/articles/1", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("missing-permission", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/articles/1", strings.NewReader(""))
+		assert.NoError(t, err)
+		req = withPermissions(req, models.PermissionArticleCreate, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	mockUCase.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestUpdate(t *testing.T) {
+	mockArticle := models.Article{
+		ID:      42,
+		Title:   "Title",
+		Content: "Content",
+		Version: 3,
+	}
+	j, err := json.Marshal(controller.ArticleRequest{Title: mockArticle.Title, Content: mockArticle.Content})
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(p service.ArticleParam) bool {
+			return p.ID == mockArticle.ID && p.Version == 2
+		})).Return(mockArticle, nil)
+
+		e := echo.New()
+		e.Validator = httpServer.NewValidator()
+		req, err := http.NewRequest(echo.PUT, "/articles/42", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"2"`)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-if-match", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		e := echo.New()
+		e.Validator = httpServer.NewValidator()
+		req, err := http.NewRequest(echo.PUT, "/articles/42", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("service.ArticleParam")).Return(models.Article{}, utility.ErrVersionConflict)
+
+		e := echo.New()
+		e.Validator = httpServer.NewValidator()
+		req, err := http.NewRequest(echo.PUT, "/articles/42", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"1"`)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("malformed-if-match", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		e := echo.New()
+		e.Validator = httpServer.NewValidator()
+		req, err := http.NewRequest(echo.PUT, "/articles/42", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", "garbage")
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockArticle := models.Article{ID: 3, Title: "Hello", Version: 5}
+		mockUCase.On("Restore", mock.Anything, int64(3), int64(2), int64(4)).Return(mockArticle, nil)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/articles/3/revisions/2/restore", strings.NewReader(""))
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"4"`)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-if-match", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/articles/3/revisions/2/restore", strings.NewReader(""))
+		assert.NoError(t, err)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		mockUCase.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Restore", mock.Anything, int64(3), int64(2), int64(1)).Return(models.Article{}, utility.ErrVersionConflict)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/articles/3/revisions/2/restore", strings.NewReader(""))
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"1"`)
+		req = withPermissions(req, models.PermissionArticleUpdate)
+
+		rec := httptest.NewRecorder()
+		controller.InitArticleController(e, mockUCase)
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDiffBadVersion(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles/3/revisions/diff?from=1&to=x", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+
+	controller.InitArticleController(e, mockUCase)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestTrash(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Trash", mock.Anything, "", int64(0)).Return([]models.Article{}, "", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles/trash", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	controller.InitArticleController(e, mockUCase)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, withPermissions(req))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
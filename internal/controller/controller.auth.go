@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type authController struct {
+	AuthService service.AuthService
+}
+
+// InitAuthController will initialize the auth's HTTP controller
+func InitAuthController(e *echo.Echo, as service.AuthService) {
+	controller := &authController{
+		AuthService: as,
+	}
+	e.POST("/auth/login", controller.Login)
+	e.POST("/auth/refresh", controller.Refresh)
+	e.POST("/auth/logout", controller.Logout)
+}
+
+// LoginRequest login body request
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshRequest refresh and logout body request
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Login godoc
+// @Summary Log a User in
+// @Description Exchange email and password for an access and a refresh token
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param credential body LoginRequest true "Credential"
+// @Success 200 {object} models.Token
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/login [post]
+func (a *authController) Login(c echo.Context) error {
+	var loginRequest LoginRequest
+	err := c.Bind(&loginRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&loginRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	token, err := a.AuthService.Login(ctx, loginRequest.Email, loginRequest.Password)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, token)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Rotate a refresh token into a new access and refresh token
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param token body RefreshRequest true "Refresh token"
+// @Success 200 {object} models.Token
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/refresh [post]
+func (a *authController) Refresh(c echo.Context) error {
+	var refreshRequest RefreshRequest
+	err := c.Bind(&refreshRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&refreshRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	token, err := a.AuthService.Refresh(ctx, refreshRequest.RefreshToken)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, token)
+}
+
+// Logout godoc
+// @Summary Log a User out
+// @Description Revoke a refresh token
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param token body RefreshRequest true "Refresh token"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /auth/logout [post]
+func (a *authController) Logout(c echo.Context) error {
+	var refreshRequest RefreshRequest
+	err := c.Bind(&refreshRequest)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&refreshRequest); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.AuthService.Logout(ctx, refreshRequest.RefreshToken)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type breakerController struct {
+	BService service.BreakerService
+}
+
+// InitBreakerController will initialize the breaker's HTTP controller
+func InitBreakerController(e *echo.Echo, bs service.BreakerService) {
+	controller := &breakerController{
+		BService: bs,
+	}
+	e.GET("/admin/breakers", controller.Fetch, requirePermission(models.PermissionSystemRead))
+}
+
+// Fetch godoc
+// @Summary Show the Circuit Breakers
+// @Description get the state, counters, error percentage and latency of every outbound dependency
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.BreakerStatus
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /admin/breakers [get]
+func (b *breakerController) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := b.BService.Fetch(ctx)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type domainController struct {
+	DService service.DomainService
+}
+
+// InitDomainController will initialize the domain's HTTP controller
+func InitDomainController(e *echo.Echo, ds service.DomainService) {
+	controller := &domainController{
+		DService: ds,
+	}
+	e.GET("/domains/:domain/availability", controller.GetDomainAvailable)
+	// a batch fans out into many GoDaddy calls on our credentials
+	e.POST("/domains/availability", controller.GetDomainsAvailable, requirePermission())
+}
+
+// GetDomainAvailable godoc
+// @Summary Show a Domain availability
+// @Description check whether a domain can be registered, and its price
+// @Tags domains
+// @Accept  json
+// @Produce  json
+// @Param domain path string true "Domain name"
+// @Success 200 {object} models.DomainAvailableResponse
+// @Failure 400 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Failure 504 {object} models.Problem
+// @Router /domains/{domain}/availability [get]
+func (d *domainController) GetDomainAvailable(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := d.DService.GetDomainAvailable(ctx, c.Param("domain"))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetDomainsAvailable godoc
+// @Summary Check many Domains availability
+// @Description check a batch of domains, each one gets its own result or error
+// @Tags domains
+// @Accept  json
+// @Produce  json
+// @Param request body models.DomainAvailableRequest true "Domains to check"
+// @Success 200 {object} models.DomainAvailableBulkResponse
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /domains/availability [post]
+func (d *domainController) GetDomainsAvailable(c echo.Context) error {
+	var request models.DomainAvailableRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&request); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := d.DService.GetDomainsAvailable(ctx, request.Domains)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
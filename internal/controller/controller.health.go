@@ -20,6 +20,8 @@ func InitHealthController(e *echo.Echo, healthService service.HealthService) {
 		healthService: healthService,
 	}
 	e.GET("/health", controller.CheckHealth)
+	e.GET("/health/live", controller.Liveness)
+	e.GET("/health/ready", controller.Readiness)
 }
 
 // CheckHealth godoc
@@ -29,9 +31,7 @@ func InitHealthController(e *echo.Echo, healthService service.HealthService) {
 // @Accept json
 // @Produce json
 // @Header 200 {string} models.BaseResponse
-// @Failure 400 {object} models.BaseResponse
-// @Failure 404 {object} models.BaseResponse
-// @Failure 500 {object} models.BaseResponse
+// @Failure 500 {object} models.Problem
 // @Router /health [get]
 func (h healthController) CheckHealth(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -40,7 +40,44 @@ func (h healthController) CheckHealth(c echo.Context) error {
 	}
 	err := h.healthService.CheckHealth(ctx)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 	return c.JSON(http.StatusOK, models.BaseResponse{Code: "SUCCESS", Message: "SUCCESS", Data: true})
 }
+
+// Liveness godoc
+// @Summary Show the Liveness
+// @Description tells the process is running, no dependency is checked
+// @Tags health
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Health
+// @Router /health/live [get]
+func (h healthController) Liveness(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.JSON(http.StatusOK, h.healthService.Liveness(ctx))
+}
+
+// Readiness godoc
+// @Summary Show the Readiness
+// @Description check every dependency, answers 503 when a critical one is down or the server is shutting down
+// @Tags health
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Health
+// @Failure 503 {object} models.Health
+// @Router /health/ready [get]
+func (h healthController) Readiness(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	res := h.healthService.Readiness(ctx)
+	if res.Status == models.HealthDown {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type roleController struct {
+	RService service.RoleService
+}
+
+// InitRoleController will initialize the role's HTTP controller
+func InitRoleController(e *echo.Echo, rs service.RoleService) {
+	controller := &roleController{
+		RService: rs,
+	}
+	manage := requirePermission(models.PermissionUserManage)
+	e.GET("/roles", controller.Fetch, manage)
+	e.GET("/users/:id/roles", controller.GetByUser, manage)
+	e.PUT("/users/:id/roles/:role", controller.Assign, manage)
+	e.DELETE("/users/:id/roles/:role", controller.Revoke, manage)
+}
+
+// Fetch godoc
+// @Summary Show the Roles
+// @Description get every role with its permissions
+// @Tags roles
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Role
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /roles [get]
+func (r *roleController) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	roles, err := r.RService.Fetch(ctx)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+// GetByUser godoc
+// @Summary Show the Roles of a User
+// @Description get the roles granted to a user
+// @Tags roles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {array} models.Role
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/roles [get]
+func (r *roleController) GetByUser(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	roles, err := r.RService.GetByUser(ctx, int64(idP))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, roles)
+}
+
+// Assign godoc
+// @Summary Grant a Role
+// @Description grant a role to a user
+// @Tags roles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param role path string true "Role name"
+// @Success 204
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/roles/{role} [put]
+func (r *roleController) Assign(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = r.RService.Assign(ctx, int64(idP), c.Param("role"))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Revoke godoc
+// @Summary Revoke a Role
+// @Description take a role away from a user
+// @Tags roles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param role path string true "Role name"
+// @Success 204
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/roles/{role} [delete]
+func (r *roleController) Revoke(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = r.RService.Revoke(ctx, int64(idP), c.Param("role"))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+type tagController struct {
+	TService service.TagService
+}
+
+// InitTagController will initialize the tag's HTTP controller
+func InitTagController(e *echo.Echo, ts service.TagService) {
+	controller := &tagController{
+		TService: ts,
+	}
+	e.GET("/tags", controller.Fetch)
+}
+
+// Fetch godoc
+// @Summary Show the Tags
+// @Description get the tags in use with the number of articles carrying each, the most used first
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Tag
+// @Failure 500 {object} models.Problem
+// @Router /tags [get]
+func (t *tagController) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tags, err := t.TService.Fetch(ctx)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
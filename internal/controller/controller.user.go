@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
 	"github.com/labstack/echo/v4"
 )
@@ -19,10 +20,56 @@ func InitUserController(e *echo.Echo, us service.UserService) {
 	controller := &userController{
 		UService: us,
 	}
-	// e.GET("/users", controller.FetchArticle)
+	e.GET("/users", controller.FetchUser, requirePermission(models.PermissionUserRead))
+	e.POST("/users", controller.Store)
+	e.GET("/users/:id", controller.GetByID, requirePermission(models.PermissionUserRead))
+	e.PATCH("/users/:id", controller.Update, requirePermission())
+	e.DELETE("/users/:id", controller.Delete, requirePermission())
+	e.PUT("/users/:id/password", controller.UpdatePassword, requirePermission())
+
+	// singular paths kept for the clients written against them
 	e.POST("/user", controller.Store)
-	e.GET("/user/:id", controller.GetByID)
-	// e.DELETE("/articles/:id", controller.Delete)
+	e.GET("/user/:id", controller.GetByID, requirePermission(models.PermissionUserRead))
+}
+
+// FetchUser godoc
+// @Summary List Users
+// @Description List users, newest first, optionally filtered on a part of their email or name
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param num query int false "num"
+// @Param cursor query string false "cursor"
+// @Param email query string false "part of the email"
+// @Param name query string false "part of the name"
+// @Success 200 {array} models.User
+// @Header 200 {string} X-Cursor "Cursor of the next page"
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users [get]
+func (a *userController) FetchUser(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	filter := models.UserFilter{
+		Email: c.QueryParam("email"),
+		Name:  c.QueryParam("name"),
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	listUser, nextCursor, err := a.UService.Fetch(ctx, filter, cursor, int64(num))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listUser)
 }
 
 // GetUser godoc
@@ -33,14 +80,18 @@ func InitUserController(e *echo.Echo, us service.UserService) {
 // @Produce  json
 // @Param id path int true "User ID"
 // @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id} [get]
 // @Router /user/{id} [get]
 func (a *userController) GetByID(c echo.Context) error {
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
 	}
 
 	ctx := c.Request().Context()
@@ -51,7 +102,7 @@ func (a *userController) GetByID(c echo.Context) error {
 	id := int64(idP)
 	art, err := a.UService.GetByID(ctx, id)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, art)
@@ -59,8 +110,8 @@ func (a *userController) GetByID(c echo.Context) error {
 
 // UserRequest user body request
 type UserRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Name     string `json:"name" validate:"required,max=45"`
+	Email    string `json:"email" validate:"required,email,max=45"`
 	Password string `json:"password" validate:"required"`
 }
 
@@ -71,16 +122,22 @@ type UserRequest struct {
 // @Accept  json
 // @Produce  json
 // @Param user body UserRequest true "User Body"
+// @Success 201 {object} models.User
 // @Header 200 {string} Token "qwerty"
-// @Failure 400 {object} ResponseError
-// @Failure 404 {object} ResponseError
-// @Failure 500 {object} ResponseError
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /users [post]
 // @Router /user [post]
 func (a *userController) Store(c echo.Context) error {
 	var userRequest UserRequest
 	err := c.Bind(&userRequest)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&userRequest); err != nil {
+		return utility.RenderError(c, err)
 	}
 
 	userParam := service.UserParam{
@@ -94,10 +151,151 @@ func (a *userController) Store(c echo.Context) error {
 		ctx = context.Background()
 	}
 
-	_, err = a.UService.Store(ctx, userParam)
+	user, err := a.UService.Store(ctx, userParam)
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+// UserPatchRequest user body request of a partial update, omitted fields are left as they are
+type UserPatchRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=45"`
+	Email *string `json:"email" validate:"omitempty,email,max=45"`
+}
+
+// Update godoc
+// @Summary Update an User
+// @Description Update the name or the email of an User, users may edit themselves only unless they manage users. A new email has to be verified again.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param user body UserPatchRequest true "User Body"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 409 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id} [patch]
+func (a *userController) Update(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	var ur UserPatchRequest
+	err = c.Bind(&ur)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&ur); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	user, err := a.UService.Update(ctx, service.UserPatchParam{
+		ID:    int64(idP),
+		Name:  ur.Name,
+		Email: ur.Email,
+	})
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// Delete godoc
+// @Summary Delete an User
+// @Description Delete an User and sign it out, users may delete themselves only unless they manage users
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id} [delete]
+func (a *userController) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	err = a.UService.Delete(ctx, int64(idP))
+	if err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PasswordRequest password change body request
+type PasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// UpdatePassword godoc
+// @Summary Change the password of an User
+// @Description Change the password of the caller, the current password has to be confirmed. Every refresh token of the User is revoked.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param password body PasswordRequest true "Password Body"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 422 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Security ApiKeyAuth
+// @Router /users/{id}/password [put]
+func (a *userController) UpdatePassword(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utility.RenderError(c, utility.ErrBadParamInput.Wrap(err))
+	}
+
+	var pr PasswordRequest
+	err = c.Bind(&pr)
+	if err != nil {
+		return utility.RenderError(c, utility.ErrUnprocessableEntity.Wrap(err))
+	}
+	if err = c.Validate(&pr); err != nil {
+		return utility.RenderError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.UService.UpdatePassword(ctx, int64(idP), pr.OldPassword, pr.NewPassword)
 	if err != nil {
-		return c.JSON(utility.GetStatusCode(err), ResponseError{Message: err.Error()})
+		return utility.RenderError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, userParam)
+	return c.NoContent(http.StatusNoContent)
 }
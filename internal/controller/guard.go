@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+// requirePermission guards a route so that only authenticated callers holding every
+// listed permission reach the handler. It is meant to be attached at route registration:
+//
+//	e.DELETE("/articles/:id", controller.Delete, requirePermission(models.PermissionArticleDelete))
+func requirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := utility.AuthUserFromContext(c.Request().Context())
+			if !ok {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return utility.RenderError(c, utility.ErrUnauthorized)
+			}
+
+			for _, p := range permissions {
+				if !user.HasPermission(p) {
+					return utility.RenderError(c, utility.ErrForbidden)
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -7,4 +7,11 @@ var Module = fx.Invoke(
 	InitArticleController,
 	InitUserController,
 	InitHealthController,
+	InitAuthController,
+	InitAccountController,
+	InitRoleController,
+	InitDomainController,
+	InitBreakerController,
+	InitAddressController,
+	InitTagController,
 )
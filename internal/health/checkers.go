@@ -0,0 +1,54 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"gorm.io/gorm"
+)
+
+// SQL checks a database/sql pool by pinging it
+func SQL(name string, db *sql.DB) Checker {
+	return Checker{
+		Name:     name,
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+	}
+}
+
+// Gorm checks the pool underneath a gorm handle by pinging it
+func Gorm(name string, db *gorm.DB) Checker {
+	return Checker{
+		Name:     name,
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}
+}
+
+// Breaker fails while the circuit breaker of an outbound dependency is open. A breaker
+// that has not seen a call yet is fine. Outbound dependencies only degrade readiness,
+// the breaker already answers for them while they are down.
+func Breaker(name string) Checker {
+	return Checker{
+		Name: "breaker:" + name,
+		Check: func(ctx context.Context) error {
+			for _, s := range utility.BreakerStatuses() {
+				if s.Name == name && s.State == models.BreakerOpen {
+					return fmt.Errorf("circuit is open at %.0f%% errors", s.ErrorPercent)
+				}
+			}
+			return nil
+		},
+	}
+}
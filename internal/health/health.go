@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kecci/goscription/models"
+)
+
+// defaultTimeout bounds the checkers registered without a timeout of their own
+const defaultTimeout = 2 * time.Second
+
+// Checker is one dependency the readiness probe asks about. A failing critical checker
+// fails the probe, any other one only degrades it.
+type Checker struct {
+	Name     string
+	Timeout  time.Duration
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// Registry holds the checkers every subsystem contributes and knows whether the
+// application is shutting down
+type Registry struct {
+	mutex    sync.RWMutex
+	checkers []Checker
+	timeout  time.Duration
+	draining int32
+}
+
+// NewRegistry will create an empty registry, checkers without a timeout get config.Health.Timeout
+func NewRegistry(config models.Config) *Registry {
+	timeout := time.Duration(config.Health.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Registry{timeout: timeout}
+}
+
+// Register adds checkers, they are reported in the order they were registered
+func (r *Registry) Register(checkers ...Checker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, c := range checkers {
+		if c.Timeout <= 0 {
+			c.Timeout = r.timeout
+		}
+		r.checkers = append(r.checkers, c)
+	}
+}
+
+// Drain flips readiness to failing for the rest of the process lifetime
+func (r *Registry) Drain() {
+	atomic.StoreInt32(&r.draining, 1)
+}
+
+// Draining tells whether Drain was called
+func (r *Registry) Draining() bool {
+	return atomic.LoadInt32(&r.draining) == 1
+}
+
+// Check runs every checker concurrently, each within its own timeout. Once the
+// registry drains the checkers are skipped and the answer is down.
+func (r *Registry) Check(ctx context.Context) models.Health {
+	if r.Draining() {
+		return models.Health{Status: models.HealthDown, ShuttingDown: true}
+	}
+
+	r.mutex.RLock()
+	checkers := append([]Checker{}, r.checkers...)
+	r.mutex.RUnlock()
+
+	components := make([]models.ComponentHealth, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			components[i] = run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	res := models.Health{Status: models.HealthUp, Components: components}
+	for _, c := range components {
+		if c.Status == models.HealthUp {
+			continue
+		}
+		if c.Critical {
+			res.Status = models.HealthDown
+			break
+		}
+		res.Status = models.HealthDegraded
+	}
+	return res
+}
+
+// run gives up on a checker at its timeout even when the checker ignores ctx
+func run(ctx context.Context, c Checker) models.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := models.ComponentHealth{
+		Name:      c.Name,
+		Status:    models.HealthUp,
+		Critical:  c.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = models.HealthDown
+		res.Error = err.Error()
+	}
+	return res
+}
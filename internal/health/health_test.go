@@ -0,0 +1,86 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kecci/goscription/internal/health"
+	"github.com/kecci/goscription/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func checker(name string, critical bool, err error) health.Checker {
+	return health.Checker{
+		Name:     name,
+		Critical: critical,
+		Check: func(ctx context.Context) error {
+			return err
+		},
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	t.Run("up", func(t *testing.T) {
+		r := health.NewRegistry(models.Config{})
+		r.Register(checker("db", true, nil), checker("api", false, nil))
+
+		res := r.Check(context.TODO())
+
+		assert.Equal(t, models.HealthUp, res.Status)
+		assert.Len(t, res.Components, 2)
+		assert.Equal(t, "db", res.Components[0].Name)
+		assert.Equal(t, "api", res.Components[1].Name)
+	})
+	t.Run("degraded", func(t *testing.T) {
+		r := health.NewRegistry(models.Config{})
+		r.Register(checker("db", true, nil), checker("api", false, errors.New("circuit is open")))
+
+		res := r.Check(context.TODO())
+
+		assert.Equal(t, models.HealthDegraded, res.Status)
+		assert.Equal(t, "circuit is open", res.Components[1].Error)
+	})
+	t.Run("down", func(t *testing.T) {
+		r := health.NewRegistry(models.Config{})
+		r.Register(checker("api", false, errors.New("circuit is open")), checker("db", true, errors.New("connection refused")))
+
+		res := r.Check(context.TODO())
+
+		assert.Equal(t, models.HealthDown, res.Status)
+		assert.Equal(t, models.HealthDown, res.Components[1].Status)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		r := health.NewRegistry(models.Config{Health: models.HealthProbe{Timeout: 20}})
+		r.Register(health.Checker{
+			Name:     "stuck",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		})
+
+		start := time.Now()
+		res := r.Check(context.TODO())
+
+		assert.True(t, time.Since(start) < 500*time.Millisecond)
+		assert.Equal(t, models.HealthDown, res.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), res.Components[0].Error)
+	})
+}
+
+func TestRegistryDrain(t *testing.T) {
+	r := health.NewRegistry(models.Config{})
+	r.Register(checker("db", true, nil))
+	assert.False(t, r.Draining())
+
+	r.Drain()
+	res := r.Check(context.TODO())
+
+	assert.True(t, r.Draining())
+	assert.Equal(t, models.HealthDown, res.Status)
+	assert.True(t, res.ShuttingDown)
+	assert.Empty(t, res.Components)
+}
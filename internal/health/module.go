@@ -0,0 +1,22 @@
+package health
+
+import (
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/internal/outbound"
+	"go.uber.org/fx"
+)
+
+// Module for the health checks
+var Module = fx.Options(
+	fx.Provide(NewRegistry),
+	fx.Invoke(Register),
+)
+
+// Register contributes the checkers of the dependencies the application is built with
+func Register(registry *Registry, database db.Database) {
+	registry.Register(
+		SQL("mysql", database.Mysql),
+		Gorm("postgres", database.Postgres),
+		Breaker(outbound.GodaddyBreaker),
+	)
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+const bearerPrefix = "Bearer "
+
+// Authenticate verifies the bearer access token when one is sent, and attaches the
+// authenticated user to the request context. Anonymous requests pass through untouched,
+// routes that need a caller must check for one themselves.
+func (m *GoMiddleware) Authenticate(auth service.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+			if !strings.HasPrefix(header, bearerPrefix) {
+				return unauthorized(c, utility.ErrUnauthorized)
+			}
+
+			user, err := auth.ParseAccessToken(strings.TrimPrefix(header, bearerPrefix))
+			if err != nil {
+				return unauthorized(c, err)
+			}
+
+			ctx := utility.WithAuthUser(c.Request().Context(), user)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+func unauthorized(c echo.Context, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return utility.RenderError(c, err)
+}
@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+
+	httpServer "github.com/kecci/goscription/internal/http"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	authUser := models.AuthUser{ID: 7, Email: "hello@example.com"}
+
+	handler := func(c echo.Context) error {
+		user, ok := utility.AuthUserFromContext(c.Request().Context())
+		if !ok {
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.JSON(http.StatusOK, user)
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		mockAuth := new(mocks.AuthService)
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		res := test.NewRecorder()
+		m := httpServer.InitMiddleware()
+
+		err := m.Authenticate(mockAuth)(handler)(e.NewContext(req, res))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		mockAuth.AssertNotCalled(t, "ParseAccessToken", "")
+	})
+	t.Run("valid-token", func(t *testing.T) {
+		mockAuth := new(mocks.AuthService)
+		mockAuth.On("ParseAccessToken", "good").Return(authUser, nil).Once()
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer good")
+		res := test.NewRecorder()
+		m := httpServer.InitMiddleware()
+
+		err := m.Authenticate(mockAuth)(handler)(e.NewContext(req, res))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), authUser.Email)
+		mockAuth.AssertExpectations(t)
+	})
+	t.Run("invalid-token", func(t *testing.T) {
+		mockAuth := new(mocks.AuthService)
+		mockAuth.On("ParseAccessToken", "bad").Return(models.AuthUser{}, utility.ErrUnauthorized).Once()
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer bad")
+		res := test.NewRecorder()
+		m := httpServer.InitMiddleware()
+
+		err := m.Authenticate(mockAuth)(handler)(e.NewContext(req, res))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Bearer", res.Header().Get(echo.HeaderWWWAuthenticate))
+		mockAuth.AssertExpectations(t)
+	})
+}
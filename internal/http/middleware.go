@@ -1,16 +1,27 @@
 package http
 
 import (
-	"fmt"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kecci/goscription/internal/metrics"
+	"github.com/kecci/goscription/utility"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// maxRequestIDLength bounds the X-Request-ID taken from the caller
+const maxRequestIDLength = 128
+
 // GoMiddleware struct of middleware
 type GoMiddleware struct {
 	// another stuff , may be needed by middleware
@@ -19,8 +30,9 @@ type GoMiddleware struct {
 // CORS set cors by echo
 func (m *GoMiddleware) CORS(h echo.HandlerFunc) echo.HandlerFunc {
 	cors := middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+		ExposeHeaders: []string{"ETag", "X-Cursor", echo.HeaderXRequestID, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", echo.HeaderRetryAfter},
 	})
 
 	return cors(h)
@@ -32,47 +44,166 @@ func (m *GoMiddleware) Recover(h echo.HandlerFunc) echo.HandlerFunc {
 	return recover(h)
 }
 
-func makeLogEntry(c echo.Context) *log.Entry {
-	if c == nil {
-		return log.WithFields(log.Fields{
-			"at": time.Now().Format("2006-01-02 15:04:05"),
-		})
+// RequestID keeps the X-Request-ID of the caller, or generates one when it is missing
+// or malformed. The id is sent back and every line logged through the request
+// context carries it.
+func (m *GoMiddleware) RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		id := req.Header.Get(echo.HeaderXRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+		ctx := utility.WithRequestID(req.Context(), id)
+		ctx = utility.WithLogger(ctx, log.WithField("request_id", id))
+		c.SetRequest(req.WithContext(ctx))
+		return next(c)
 	}
+}
 
-	// Sanitize uri
-	uri := c.Request().URL.String()
-	escapedUri := strings.Replace(uri, "\n", "", -1)
-	escapedUri = strings.Replace(escapedUri, "\r", "", -1)
-
-	return log.WithFields(log.Fields{
-		"at":     time.Now().Format("2006-01-02 15:04:05"),
-		"method": c.Request().Method,
-		"uri":    escapedUri,
-		"ip":     c.Request().RemoteAddr,
-	})
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("-_.:", r):
+		default:
+			return false
+		}
+	}
+	return true
 }
 
-// Logger for log
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+// Logger writes one line per request once it completed, with its status, latency and size
 func (m *GoMiddleware) Logger(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if strings.Contains(c.Request().RequestURI, "swagger") {
 			return next(c)
 		}
-		makeLogEntry(c).Info("incoming request")
-		return next(c)
+
+		start := time.Now()
+		err := next(c)
+		if err != nil {
+			// render the error now, the status is only known afterwards. It is still
+			// handed on, rendering it again does nothing once the response is committed.
+			c.Error(err)
+		}
+
+		entry := requestLogger(c).WithFields(log.Fields{
+			"status":     c.Response().Status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes_in":   c.Request().ContentLength,
+			"bytes_out":  c.Response().Size,
+		})
+		if user, ok := utility.AuthUserFromContext(c.Request().Context()); ok {
+			entry = entry.WithField("user_id", user.ID)
+		}
+
+		switch status := c.Response().Status; {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+		return err
 	}
 }
 
-// ErrorHandler fot context echo
+// Trace starts the server span of the request, continuing the trace of the caller
+// when it sent a traceparent header. The logger of the request carries the trace id.
+func (m *GoMiddleware) Trace(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := utility.StartSpan(ctx, "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", "", req)...),
+		)
+		defer span.End()
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = utility.WithLogger(ctx, utility.Logger(ctx).WithField("trace_id", sc.TraceID().String()))
+		}
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		route := routeOf(c, err)
+		status := c.Response().Status
+		span.SetName(req.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(status))
+		return err
+	}
+}
+
+// Metrics counts and times every request by its route template, so the label stays
+// bounded whatever the path parameters are
+func (m *GoMiddleware) Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		route := routeOf(c, err)
+		status := strconv.Itoa(c.Response().Status)
+		metrics.HTTPRequests.WithLabelValues(c.Request().Method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(c.Request().Method, route, status).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// routeOf is the route template of the request. echo hands the raw path of a request
+// no route matched, those share one name instead.
+func routeOf(c echo.Context, err error) string {
+	if err == echo.ErrNotFound {
+		return "unmatched"
+	}
+	return c.Path()
+}
+
+// requestLogger is the logger of the request context along with the request line
+func requestLogger(c echo.Context) *log.Entry {
+	req := c.Request()
+
+	// Sanitize uri
+	uri := req.URL.String()
+	escapedURI := strings.Replace(uri, "\n", "", -1)
+	escapedURI = strings.Replace(escapedURI, "\r", "", -1)
+
+	return utility.Logger(req.Context()).WithFields(log.Fields{
+		"method": req.Method,
+		"uri":    escapedURI,
+		"route":  c.Path(),
+		"ip":     c.RealIP(),
+	})
+}
+
+// ErrorHandler fot context echo, RenderError logs the error itself
 func (m *GoMiddleware) ErrorHandler(err error, c echo.Context) {
-	report, ok := err.(*echo.HTTPError)
-	if ok {
-		report.Message = fmt.Sprintf("http error %d - %v", report.Code, report.Message)
-	} else {
-		report = echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	if rerr := utility.RenderError(c, err); rerr != nil {
+		requestLogger(c).Error(rerr)
 	}
-	makeLogEntry(c).Error(report.Message)
-	c.Echo().DefaultHTTPErrorHandler(err, c)
 }
 
 // InitMiddleware will initialize the middleware handler
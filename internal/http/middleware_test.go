@@ -1,18 +1,33 @@
 package http_test
 
 import (
+	"encoding/json"
 	"net/http"
 	test "net/http/httptest"
 	"testing"
 
 	httpServer "github.com/kecci/goscription/internal/http"
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/internal/metrics"
+	"github.com/kecci/goscription/internal/ratelimit"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestCORS(t *testing.T) {
 	e := echo.New()
 	req := test.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(echo.HeaderOrigin, "http://localhost")
 	res := test.NewRecorder()
 	c := e.NewContext(req, res)
 	m := httpServer.InitMiddleware()
@@ -24,4 +39,199 @@ func TestCORS(t *testing.T) {
 	err := h(c)
 	assert.NoError(t, err)
 	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "ETag,X-Cursor,X-Request-Id,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After", res.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestErrorHandler(t *testing.T) {
+	e := echo.New()
+	m := httpServer.InitMiddleware()
+	e.HTTPErrorHandler = m.ErrorHandler
+
+	req := test.NewRequest(echo.GET, "/nowhere", nil)
+	res := test.NewRecorder()
+	e.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, utility.MIMEApplicationProblemJSON, res.Header().Get(echo.HeaderContentType))
+
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "not_found", problem.Code)
+	assert.Equal(t, "/nowhere", problem.Instance)
+}
+
+func TestRequestID(t *testing.T) {
+	m := httpServer.InitMiddleware()
+	var ctxID string
+	h := m.RequestID(func(c echo.Context) error {
+		ctxID, _ = utility.RequestIDFromContext(c.Request().Context())
+		assert.Equal(t, ctxID, utility.Logger(c.Request().Context()).Data["request_id"])
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("kept", func(t *testing.T) {
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, "abc-123")
+		res := test.NewRecorder()
+
+		assert.NoError(t, h(echo.New().NewContext(req, res)))
+		assert.Equal(t, "abc-123", res.Header().Get(echo.HeaderXRequestID))
+		assert.Equal(t, "abc-123", ctxID)
+	})
+	t.Run("generated", func(t *testing.T) {
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, "bad id\n")
+		res := test.NewRecorder()
+
+		assert.NoError(t, h(echo.New().NewContext(req, res)))
+		assert.Len(t, res.Header().Get(echo.HeaderXRequestID), 32)
+		assert.Equal(t, res.Header().Get(echo.HeaderXRequestID), ctxID)
+	})
+}
+
+func TestLogger(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	e := echo.New()
+	m := httpServer.InitMiddleware()
+	e.HTTPErrorHandler = m.ErrorHandler
+	e.Use(m.RequestID, m.Logger)
+	e.GET("/articles/:id", func(c echo.Context) error {
+		return utility.ErrNotFound
+	})
+
+	req := test.NewRequest(echo.GET, "/articles/7", nil)
+	req.Header.Set(echo.HeaderXRequestID, "abc-123")
+	res := test.NewRecorder()
+	e.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	var completed []*logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "request completed" {
+			completed = append(completed, entry)
+		}
+	}
+	assert.Len(t, completed, 1)
+	entry := completed[0]
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "abc-123", entry.Data["request_id"])
+	assert.Equal(t, "/articles/:id", entry.Data["route"])
+	assert.Equal(t, http.StatusNotFound, entry.Data["status"])
+	assert.Equal(t, int64(res.Body.Len()), entry.Data["bytes_out"])
+}
+
+func TestMetrics(t *testing.T) {
+	e := echo.New()
+	m := httpServer.InitMiddleware()
+	e.HTTPErrorHandler = m.ErrorHandler
+	e.Use(m.Metrics)
+	e.GET("/metrics-test/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(echo.GET, "/metrics-test/:id", "204"))
+	unmatched := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(echo.GET, "unmatched", "404"))
+	e.ServeHTTP(test.NewRecorder(), test.NewRequest(echo.GET, "/metrics-test/1", nil))
+	e.ServeHTTP(test.NewRecorder(), test.NewRequest(echo.GET, "/metrics-test/2", nil))
+	e.ServeHTTP(test.NewRecorder(), test.NewRequest(echo.GET, "/metrics-nowhere", nil))
+
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(echo.GET, "/metrics-test/:id", "204")))
+	assert.Equal(t, unmatched+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(echo.GET, "unmatched", "404")))
+}
+
+func TestTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	e := echo.New()
+	m := httpServer.InitMiddleware()
+	e.HTTPErrorHandler = m.ErrorHandler
+	e.Use(m.Trace)
+	e.GET("/articles/:id", func(c echo.Context) error {
+		return utility.ErrNotFound
+	})
+
+	req := test.NewRequest(echo.GET, "/articles/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(test.NewRecorder(), req)
+	e.ServeHTTP(test.NewRecorder(), test.NewRequest(echo.GET, "/nowhere", nil))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "GET /articles/:id", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Equal(t, "GET unmatched", spans[1].Name)
+	assert.False(t, spans[1].Parent.IsValid())
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter(models.Config{RateLimit: models.RateLimit{
+		Enabled:      true,
+		APIKeyHeader: "X-API-Key",
+		Routes: []models.RateLimitRoute{
+			{Method: echo.POST, Path: "/articles", RateLimitRule: models.RateLimitRule{Requests: 2, Period: 60, Burst: 1}},
+		},
+	}}, db.Database{})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	m := httpServer.InitMiddleware()
+	e.HTTPErrorHandler = m.ErrorHandler
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(m.RateLimit(limiter))
+	e.POST("/articles", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+	e.GET("/articles", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	post := func(remoteAddr string) *test.ResponseRecorder {
+		req := test.NewRequest(echo.POST, "/articles", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "10.9.9.9")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+		return res
+	}
+
+	res := post("10.0.0.1:1234")
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "1", res.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", res.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", res.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60;burst=1", res.Header().Get("RateLimit-Policy"))
+
+	res = post("10.0.0.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.Equal(t, "30", res.Header().Get(echo.HeaderRetryAfter))
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, "too_many_requests", problem.Code)
+
+	// the forwarded header is not trusted, another address has its own budget
+	assert.Equal(t, http.StatusCreated, post("10.0.0.2:1234").Code)
+
+	// a made up API key does not give the address a fresh budget
+	req := test.NewRequest(echo.POST, "/articles", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-API-Key", "made-up")
+	rec := test.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// routes without a rule are not limited
+	req = test.NewRequest(echo.GET, "/articles", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	rec = test.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
 }
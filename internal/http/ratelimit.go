@@ -0,0 +1,82 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/kecci/goscription/internal/ratelimit"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimit spends a token of the caller on every request and answers 429 once the
+// budget of the route is spent. It runs after Authenticate, so the user id is known.
+// A failing store lets the request through rather than taking the API down.
+func (m *GoMiddleware) RateLimit(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			var (
+				rule ratelimit.Rule
+				res  ratelimit.Result
+			)
+			for i, client := range clientKeys(c, limiter.APIKeyHeader()) {
+				r, taken, err := limiter.Take(ctx, c.Request().Method, c.Path(), client)
+				if err != nil {
+					utility.Logger(ctx).Error(err)
+					return next(c)
+				}
+				if r.Unlimited() {
+					return next(c)
+				}
+				if i == 0 || tighter(taken, res) {
+					rule, res = r, taken
+				}
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(int(res.Reset.Seconds())))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", rule.Requests, int(rule.Period.Seconds()), res.Limit))
+			if !res.Allowed {
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(int(res.RetryAfter.Seconds())))
+				return utility.ErrTooManyRequests
+			}
+			return next(c)
+		}
+	}
+}
+
+// clientKeys names the buckets a request spends from: the user id, or the IP for
+// anonymous callers, and on top of it the API key when one is sent. Keys are not
+// checked, a made up one only adds a budget and never replaces the first.
+func clientKeys(c echo.Context, apiKeyHeader string) []string {
+	keys := []string{"ip:" + c.RealIP()}
+	if user, ok := utility.AuthUserFromContext(c.Request().Context()); ok {
+		keys[0] = "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	if apiKeyHeader != "" {
+		if key := c.Request().Header.Get(apiKeyHeader); key != "" {
+			sum := sha256.Sum256([]byte(key))
+			keys = append(keys, "key:"+hex.EncodeToString(sum[:]))
+		}
+	}
+	return keys
+}
+
+// tighter reports whether a leaves the client less room than b
+func tighter(a, b ratelimit.Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if a.Remaining != b.Remaining {
+		return a.Remaining < b.Remaining
+	}
+	return a.RetryAfter > b.RetryAfter
+}
@@ -2,9 +2,17 @@ package http
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"time"
 
+	"github.com/kecci/goscription/internal/health"
+	"github.com/kecci/goscription/internal/ratelimit"
+	"github.com/kecci/goscription/internal/service"
 	"github.com/kecci/goscription/models"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"go.uber.org/fx"
@@ -14,31 +22,52 @@ import (
 var Module = fx.Provide(NewServer)
 
 // NewServer initialize new server
-func NewServer(lc fx.Lifecycle, config models.Config) *echo.Echo {
+func NewServer(lc fx.Lifecycle, config models.Config, auth service.AuthService, probes *health.Registry, registry *prometheus.Registry, limiter *ratelimit.Limiter) *echo.Echo {
 	instance := echo.New()
+	instance.IPExtractor = echo.ExtractIPDirect()
+	if config.Server.TrustProxy {
+		instance.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
 
 	// Middleware
 	middL := InitMiddleware()
+	instance.Use(middL.RequestID)
+	instance.Use(middL.Trace)
 	instance.Use(middL.CORS)
 	instance.Use(middL.Logger)
+	instance.Use(middL.Metrics)
 	instance.Use(middL.Recover)
+	instance.Use(middL.Authenticate(auth))
+	instance.Use(middL.RateLimit(limiter))
 
 	instance.HTTPErrorHandler = middL.ErrorHandler
+	instance.Validator = NewValidator()
 
 	instance.GET("/swagger/*", echoSwagger.WrapHandler)
+	instance.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
 
 	lc.Append(fx.Hook{
 		OnStart: func(context.Context) error {
 			logrus.Print("Starting HTTP server.")
 			go func() {
+				// Start returns ErrServerClosed as soon as OnStop shuts the server down
 				err := instance.Start(config.Server.Address)
-				if err != nil {
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
 					logrus.Fatal(err)
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			// fail readiness first so the load balancer stops sending requests
+			probes.Drain()
+			if delay := time.Duration(config.Health.DrainDelay) * time.Millisecond; delay > 0 {
+				logrus.Printf("Draining HTTP server for %s.", delay)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+				}
+			}
 			logrus.Print("Stopping HTTP server.")
 			return instance.Shutdown(ctx)
 		},
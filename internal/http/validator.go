@@ -0,0 +1,90 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/labstack/echo/v4"
+)
+
+// zipCodePattern is the five digit postal code
+var zipCodePattern = regexp.MustCompile(`^[0-9]{5}$`)
+
+// Validator checks the `validate` tags of the request bodies
+type Validator struct {
+	validate *validator.Validate
+}
+
+// NewValidator will create the validator of the echo instance
+func NewValidator() echo.Validator {
+	v := validator.New()
+
+	// report the fields the way the caller sent them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	_ = v.RegisterValidation("zipcode", func(fl validator.FieldLevel) bool {
+		return zipCodePattern.MatchString(fl.Field().String())
+	})
+
+	return &Validator{validate: v}
+}
+
+// Validate answers ErrBadParamInput with one detail per failing field
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	fieldErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return utility.ErrBadParamInput.Wrap(err)
+	}
+
+	details := make([]models.ErrorDetail, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		details = append(details, models.ErrorDetail{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe),
+		})
+	}
+	return utility.ErrBadParamInput.Wrap(err).WithDetails(details...)
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "max":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "min":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "zipcode":
+		return "must be a 5 digit zip code"
+	default:
+		return fmt.Sprintf("does not satisfy %s", fe.Tag())
+	}
+}
+
+func isCollection(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
@@ -0,0 +1,48 @@
+package http_test
+
+import (
+	"errors"
+	"testing"
+
+	httpServer "github.com/kecci/goscription/internal/http"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	v := httpServer.NewValidator()
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, v.Validate(&models.User{Name: "Hello", Email: "hello@example.com", Password: "s3cret"}))
+	})
+	t.Run("email", func(t *testing.T) {
+		err := v.Validate(&models.User{Name: "Hello", Email: "not-an-email", Password: "s3cret"})
+
+		var appErr *utility.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.True(t, errors.Is(err, utility.ErrBadParamInput))
+		assert.Equal(t, []models.ErrorDetail{{Field: "email", Rule: "email", Message: "must be a valid email address"}}, appErr.Details)
+	})
+	t.Run("zipcode", func(t *testing.T) {
+		address := models.Address{UserID: 1, AddressTitle: "Home", AddressFull: "Jl. Sudirman 1", ZipCode: "12A45"}
+
+		var appErr *utility.AppError
+		assert.True(t, errors.As(v.Validate(&address), &appErr))
+		assert.Equal(t, []models.ErrorDetail{{Field: "zip_code", Rule: "zipcode", Message: "must be a 5 digit zip code"}}, appErr.Details)
+
+		address.ZipCode = "12345"
+		assert.NoError(t, v.Validate(&address))
+		address.ZipCode = ""
+		assert.NoError(t, v.Validate(&address))
+	})
+	t.Run("items", func(t *testing.T) {
+		body := struct {
+			Tags []string `json:"tags" validate:"max=2,dive,required"`
+		}{Tags: []string{"go", "echo", "sql"}}
+
+		var appErr *utility.AppError
+		assert.True(t, errors.As(v.Validate(&body), &appErr))
+		assert.Equal(t, []models.ErrorDetail{{Field: "tags", Rule: "max", Message: "must have at most 2 items"}}, appErr.Details)
+	})
+}
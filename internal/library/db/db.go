@@ -3,11 +3,13 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"net/url"
+	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/ory/viper"
-	"gorm.io/driver/mysql"
+	"go.opentelemetry.io/otel/semconv"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -17,16 +19,24 @@ type Database struct {
 	Postgres *gorm.DB
 }
 
-// NewDB initiate database
-func NewDB() Database {
-	return Database{
-		Mysql:    newMysqlDB(),
-		Postgres: newPostgresDB(),
+// NewDB initiate database, the application does not start when a database cannot be opened
+func NewDB() (Database, error) {
+	mysqlDB, err := newMysqlDB()
+	if err != nil {
+		return Database{}, fmt.Errorf("open mysql: %w", err)
+	}
+	postgresDB, err := newPostgresDB()
+	if err != nil {
+		return Database{}, fmt.Errorf("open postgres: %w", err)
 	}
+	return Database{
+		Mysql:    mysqlDB,
+		Postgres: postgresDB,
+	}, nil
 }
 
 // NewMysqlDB generate mysql database
-func newMysqlDB() *sql.DB {
+func newMysqlDB() (*sql.DB, error) {
 	dbHost := viper.GetString(`database.mysql.host`)
 	dbPort := viper.GetString(`database.mysql.port`)
 	dbUser := viper.GetString(`database.mysql.user`)
@@ -37,24 +47,49 @@ func newMysqlDB() *sql.DB {
 	val.Add("parseTime", "1")
 	val.Add("loc", "Asia/Jakarta")
 	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	db, err := sql.Open(`mysql`, dsn)
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
-		fmt.Println(err.Error())
+		return nil, err
 	}
-	return db
+	return sql.OpenDB(traceConnector(connector, semconv.DBSystemMySQL)), nil
 }
 
-// NewPostgresDB generate postgres db
-func newPostgresDB() *gorm.DB {
-	dbHost := viper.GetString(`database.postgres.host`)
-	dbPort := viper.GetString(`database.postgres.port`)
-	dbUser := viper.GetString(`database.postgres.user`)
-	dbPass := viper.GetString(`database.postgres.pass`)
-	dbName := viper.GetString(`database.postgres.name`)
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbUser, dbPass, dbHost, dbPort, dbName)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// NewPostgresDB generate postgres db, gorm pings the server while opening it
+func newPostgresDB() (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(PostgresDSN()), &gorm.Config{})
 	if err != nil {
-		log.Println(err.Error())
+		return nil, err
+	}
+	if err = db.Use(gormTracer{}); err != nil {
+		return nil, err
 	}
-	return db
+	return db, nil
+}
+
+// PostgresDSN builds the keyword/value connection string out of database.postgres
+func PostgresDSN() string {
+	sslMode := viper.GetString(`database.postgres.sslmode`)
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=Asia/Jakarta",
+		quoteDSN(viper.GetString(`database.postgres.host`)),
+		quoteDSN(viper.GetString(`database.postgres.port`)),
+		quoteDSN(viper.GetString(`database.postgres.user`)),
+		quoteDSN(viper.GetString(`database.postgres.pass`)),
+		quoteDSN(viper.GetString(`database.postgres.name`)),
+		quoteDSN(sslMode),
+	)
+}
+
+// quoteDSN keeps spaces and quotes of a value from breaking the connection string
+func quoteDSN(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return "'" + value + "'"
 }
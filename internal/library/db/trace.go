@@ -0,0 +1,255 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/kecci/goscription/utility"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracedConnector hands out connections that record a span for every statement they
+// run. The span starts once the driver accepted the statement, so the statements
+// database/sql retries as prepared ones are not recorded twice.
+type tracedConnector struct {
+	driver.Connector
+	system attribute.KeyValue
+}
+
+func traceConnector(c driver.Connector, system attribute.KeyValue) driver.Connector {
+	return &tracedConnector{Connector: c, system: system}
+}
+
+// Connect ...
+func (t *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := t.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, system: t.system}, nil
+}
+
+func recordQuery(ctx context.Context, system attribute.KeyValue, operation, query string, start time.Time, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+	_, span := utility.StartSpan(ctx, "sql."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(system, semconv.DBStatementKey.String(query)),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type tracedConn struct {
+	driver.Conn
+	system attribute.KeyValue
+}
+
+// PrepareContext ...
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query, system: c.system}, nil
+}
+
+// BeginTx ...
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+// QueryContext ...
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	recordQuery(ctx, c.system, "query", query, start, err)
+	return rows, err
+}
+
+// ExecContext ...
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	recordQuery(ctx, c.system, "exec", query, start, err)
+	return res, err
+}
+
+// Ping ...
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession ...
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// CheckNamedValue keeps the value conversions of the driver
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type tracedStmt struct {
+	driver.Stmt
+	query  string
+	system attribute.KeyValue
+}
+
+// ExecContext ...
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	recordQuery(ctx, s.system, "exec", s.query, start, err)
+	return res, err
+}
+
+// QueryContext ...
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	recordQuery(ctx, s.system, "query", s.query, start, err)
+	return rows, err
+}
+
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// gormTracer records a span for every statement gorm runs
+type gormTracer struct{}
+
+const gormSpanKey = "tracing:span"
+
+// Name ...
+func (gormTracer) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the callbacks around every kind of gorm statement
+func (gormTracer) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startGormSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endGormSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startGormSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endGormSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startGormSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endGormSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startGormSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endGormSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startGormSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endGormSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startGormSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endGormSpan),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := utility.StartSpan(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgres, semconv.DBOperationKey.String(operation)),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGormSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		semconv.DBStatementKey.String(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.RowsAffected),
+	)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+	span.End()
+}
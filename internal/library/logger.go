@@ -0,0 +1,26 @@
+package library
+
+import (
+	"time"
+
+	"github.com/kecci/goscription/models"
+	"github.com/sirupsen/logrus"
+)
+
+// InitLogger sets the format and level of the standard logger out of config
+func InitLogger(config models.Config) {
+	switch config.Log.Format {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	case "", "text":
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true, TimestampFormat: time.RFC3339})
+	default:
+		logrus.Warnf("unknown log format %q, keeping text", config.Log.Format)
+	}
+
+	if config.Debug {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+}
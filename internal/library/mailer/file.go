@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// unsafeFileChars are replaced in the recipient part of the file names
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer will create a Mailer writing every mail as an .eml file into dir,
+// or to the log when dir is empty. Nothing is delivered, it is meant for development.
+func NewFileMailer(from, dir string) Mailer {
+	return &fileMailer{from: from, dir: dir}
+}
+
+func (f *fileMailer) Send(ctx context.Context, msg Message) error {
+	data, err := compose(f.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if f.dir == "" {
+		logrus.WithField("to", msg.To).Infof("mail not sent, log driver:\n%s", data)
+		return nil
+	}
+
+	if err = os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(f.dir, name)
+	if err = ioutil.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	logrus.WithField("to", msg.To).Infof("mail written to %s", path)
+	return nil
+}
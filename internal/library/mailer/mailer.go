@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/kecci/goscription/models"
+)
+
+const (
+	// DriverSMTP sends the mails through an SMTP server
+	DriverSMTP = "smtp"
+	// DriverFile writes the mails as .eml files, meant for development
+	DriverFile = "file"
+	// DriverLog writes the mails to the log, meant for development
+	DriverLog = "log"
+)
+
+// Message is a plain text mail
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer represent the outgoing mail contract
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer will create the Mailer selected by config.Mail.Driver
+func NewMailer(config models.Config) Mailer {
+	from := config.Mail.From
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		panic("Invalid mail sender " + from)
+	}
+
+	switch strings.ToLower(config.Mail.Driver) {
+	case DriverSMTP:
+		return NewSMTPMailer(from, config.Mail.SMTP)
+	case DriverFile:
+		return NewFileMailer(from, config.Mail.Dir)
+	case DriverLog, "":
+		return NewFileMailer(from, "")
+	default:
+		panic("Unknown mail driver " + config.Mail.Driver)
+	}
+}
+
+// compose renders msg as an RFC 5322 message, the body is quoted-printable encoded
+func compose(from string, msg Message) ([]byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err = w.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kecci/goscription/internal/library/mailer"
+	"github.com/kecci/goscription/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTP accepts a single conversation, without STARTTLS nor AUTH, and hands over
+// the envelope and the data it received
+type fakeSMTP struct {
+	listener net.Listener
+	received chan []string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	f := &fakeSMTP{listener: l, received: make(chan []string, 1)}
+	go f.serve()
+	return f
+}
+
+func (f *fakeSMTP) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	var lines []string
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250 fake")
+		case "MAIL", "RCPT":
+			lines = append(lines, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			for {
+				data, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				data = strings.TrimRight(data, "\r\n")
+				if data == "." {
+					break
+				}
+				lines = append(lines, data)
+			}
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			f.received <- lines
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	server := newFakeSMTP(t)
+	defer server.listener.Close()
+
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	assert.NoError(t, err)
+
+	m := mailer.NewMailer(models.Config{Mail: models.Mail{
+		Driver: mailer.DriverSMTP,
+		From:   "Goscription <no-reply@example.com>",
+		SMTP:   models.SMTP{Host: host, Port: port},
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = m.Send(ctx, mailer.Message{To: "hello@example.com", Subject: "Verify your email", Body: "Hello"})
+	assert.NoError(t, err)
+
+	select {
+	case lines := <-server.received:
+		all := strings.Join(lines, "\n")
+		assert.Contains(t, all, "MAIL FROM:<no-reply@example.com>")
+		assert.Contains(t, all, "RCPT TO:<hello@example.com>")
+		assert.Contains(t, all, "Subject: Verify your email")
+		assert.Contains(t, all, "To: <hello@example.com>")
+		assert.Equal(t, "Hello", lines[len(lines)-1])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := mailer.NewMailer(models.Config{Mail: models.Mail{Driver: mailer.DriverFile, Dir: dir}})
+
+	err = m.Send(context.TODO(), mailer.Message{To: "hello@example.com", Subject: "Reset your password", Body: "Hello"})
+	assert.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-hello@example.com.eml"))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		data, err := ioutil.ReadFile(files[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "Subject: Reset your password")
+	}
+}
+
+func TestMailerRejectsInvalidRecipient(t *testing.T) {
+	m := mailer.NewMailer(models.Config{Mail: models.Mail{Driver: mailer.DriverLog}})
+
+	err := m.Send(context.TODO(), mailer.Message{To: "hello@example.com\r\nBcc: x@example.com", Subject: "Hi"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/kecci/goscription/models"
+)
+
+type smtpMailer struct {
+	from   string
+	config models.SMTP
+}
+
+// NewSMTPMailer will create a Mailer delivering through the SMTP server of config. STARTTLS
+// is used whenever the server offers it, and PLAIN authentication when a username is set.
+func NewSMTPMailer(from string, config models.SMTP) Mailer {
+	if config.Host == "" {
+		panic("SMTP host is empty")
+	}
+	if config.Port == "" {
+		config.Port = "25"
+	}
+	return &smtpMailer{from: from, config: config}
+}
+
+func (s *smtpMailer) Send(ctx context.Context, msg Message) (err error) {
+	data, err := compose(s.from, msg)
+	if err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return err
+	}
+	rcpt, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(s.config.Host, s.config.Port))
+	if err != nil {
+		return err
+	}
+	// net/smtp knows nothing of contexts, the deadline bounds the whole conversation
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Minute)
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+	if s.config.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(sender.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(rcpt.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,81 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kecci/goscription/models"
+)
+
+const (
+	// AlgorithmBcrypt hashes passwords with bcrypt
+	AlgorithmBcrypt = "bcrypt"
+	// AlgorithmArgon2id hashes passwords with argon2id
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	// ErrUnknownHash will throw if the encoded hash belongs to no supported algorithm
+	ErrUnknownHash = errors.New("Unknown password hash format")
+	// ErrMalformedHash will throw if the encoded hash cannot be decoded
+	ErrMalformedHash = errors.New("Malformed password hash")
+)
+
+// Hasher represent the password hashing contract
+type Hasher interface {
+	// Hash returns the encoded hash of plain, parameters included
+	Hash(plain string) (encoded string, err error)
+	// Verify reports whether plain matches an encoded hash produced by any supported algorithm
+	Verify(plain, encoded string) (ok bool, err error)
+	// NeedsRehash reports whether encoded was produced with another algorithm or other parameters
+	NeedsRehash(encoded string) bool
+}
+
+// algorithm is a single hashing scheme able to recognise its own encoded hashes
+type algorithm interface {
+	Hasher
+	Owns(encoded string) bool
+}
+
+type hasher struct {
+	current    algorithm
+	algorithms []algorithm
+}
+
+// NewHasher will create a Hasher using the algorithm configured in config.Password,
+// while still verifying hashes produced by the other supported algorithms
+func NewHasher(config models.Config) Hasher {
+	bc := newBcrypt(config.Password.BcryptCost)
+	ar := newArgon2id(config.Password.Argon2id)
+
+	h := &hasher{algorithms: []algorithm{bc, ar}}
+	switch strings.ToLower(config.Password.Algorithm) {
+	case AlgorithmArgon2id:
+		h.current = ar
+	case AlgorithmBcrypt, "":
+		h.current = bc
+	default:
+		panic("Unknown password algorithm " + config.Password.Algorithm)
+	}
+	return h
+}
+
+func (h *hasher) Hash(plain string) (string, error) {
+	return h.current.Hash(plain)
+}
+
+func (h *hasher) Verify(plain, encoded string) (bool, error) {
+	for _, a := range h.algorithms {
+		if a.Owns(encoded) {
+			return a.Verify(plain, encoded)
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+func (h *hasher) NeedsRehash(encoded string) bool {
+	if !h.current.Owns(encoded) {
+		return true
+	}
+	return h.current.NeedsRehash(encoded)
+}
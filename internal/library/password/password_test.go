@@ -0,0 +1,78 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kecci/goscription/internal/library/password"
+	"github.com/kecci/goscription/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConfig(algorithm string, bcryptCost int, argonTime uint32) models.Config {
+	return models.Config{
+		Password: models.Password{
+			Algorithm:  algorithm,
+			BcryptCost: bcryptCost,
+			Argon2id: models.Argon2id{
+				Time:       argonTime,
+				Memory:     1024,
+				Threads:    1,
+				KeyLength:  32,
+				SaltLength: 16,
+			},
+		},
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for _, algorithm := range []string{password.AlgorithmBcrypt, password.AlgorithmArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			h := password.NewHasher(newConfig(algorithm, 4, 1))
+
+			encoded, err := h.Hash("s3cret")
+			assert.NoError(t, err)
+			assert.NotContains(t, encoded, "s3cret")
+
+			ok, err := h.Verify("s3cret", encoded)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = h.Verify("wrong", encoded)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.False(t, h.NeedsRehash(encoded))
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcryptHash, err := password.NewHasher(newConfig(password.AlgorithmBcrypt, 4, 1)).Hash("s3cret")
+	assert.NoError(t, err)
+	argonHash, err := password.NewHasher(newConfig(password.AlgorithmArgon2id, 4, 1)).Hash("s3cret")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(argonHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	t.Run("bcrypt-cost-changed", func(t *testing.T) {
+		h := password.NewHasher(newConfig(password.AlgorithmBcrypt, 5, 1))
+		assert.True(t, h.NeedsRehash(bcryptHash))
+	})
+	t.Run("argon2id-params-changed", func(t *testing.T) {
+		h := password.NewHasher(newConfig(password.AlgorithmArgon2id, 4, 2))
+		assert.True(t, h.NeedsRehash(argonHash))
+	})
+	t.Run("algorithm-changed", func(t *testing.T) {
+		h := password.NewHasher(newConfig(password.AlgorithmArgon2id, 4, 1))
+		assert.True(t, h.NeedsRehash(bcryptHash))
+
+		ok, err := h.Verify("s3cret", bcryptHash)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+	t.Run("unknown-hash", func(t *testing.T) {
+		h := password.NewHasher(newConfig(password.AlgorithmBcrypt, 4, 1))
+		_, err := h.Verify("s3cret", "s3cret")
+		assert.Equal(t, password.ErrUnknownHash, err)
+	})
+}
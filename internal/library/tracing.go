@@ -0,0 +1,73 @@
+package library
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kecci/goscription/models"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.uber.org/fx"
+)
+
+// InitTracing installs the global tracer provider out of config. The W3C trace context
+// is propagated even when no exporter is set, so the callers keep their traces.
+func InitTracing(lc fx.Lifecycle, config models.Config) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch config.Tracing.Exporter {
+	case "", "none":
+		return nil
+	case "stdout":
+		exporter, err = stdout.NewExporter(stdout.WithPrettyPrint(), stdout.WithoutMetricExport())
+	case "otlp":
+		opts := []otlphttp.Option{
+			otlphttp.WithEndpoint(config.Tracing.OTLP.Endpoint),
+			otlphttp.WithHeaders(config.Tracing.OTLP.Headers),
+		}
+		if config.Tracing.OTLP.Insecure {
+			opts = append(opts, otlphttp.WithInsecure())
+		}
+		exporter, err = otlp.NewExporter(context.Background(), otlphttp.NewDriver(opts...))
+	default:
+		return fmt.Errorf("unknown tracing exporter %q", config.Tracing.Exporter)
+	}
+	if err != nil {
+		return fmt.Errorf("tracing exporter: %w", err)
+	}
+
+	serviceName := config.Tracing.ServiceName
+	if serviceName == "" {
+		serviceName = "goscription"
+	}
+	ratio := config.Tracing.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			logrus.Print("Flushing traces.")
+			return provider.Shutdown(ctx)
+		},
+	})
+	return nil
+}
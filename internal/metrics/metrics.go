@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/utility"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/fx"
+)
+
+// Module for the metrics registry
+var Module = fx.Provide(NewRegistry)
+
+var (
+	// HTTPRequests counts the served requests per route template and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Served HTTP requests per method, route and status.",
+	}, []string{"method", "route", "status"})
+	// HTTPDuration observes the latency of the served requests per route template and status
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the served HTTP requests per method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// ArticlesCreated counts the stored articles
+	ArticlesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "articles_created_total",
+		Help: "Articles created.",
+	})
+	// ArticlesPublished counts the scheduled articles the scheduler published
+	ArticlesPublished = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "articles_scheduled_published_total",
+		Help: "Scheduled articles published when their publish_at came.",
+	})
+	// UsersRegistered counts the signed up users
+	UsersRegistered = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "users_registered_total",
+		Help: "Users signed up.",
+	})
+)
+
+// NewRegistry will create the registry /metrics exposes: the runtime, the HTTP server,
+// the database pools, the outbound breakers and the business counters
+func NewRegistry(database db.Database) (*prometheus.Registry, error) {
+	postgres, err := database.Postgres.DB()
+	if err != nil {
+		return nil, fmt.Errorf("postgres pool: %w", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	cs := []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(database.Mysql, "mysql"),
+		collectors.NewDBStatsCollector(postgres, "postgres"),
+		HTTPRequests,
+		HTTPDuration,
+		ArticlesCreated,
+		ArticlesPublished,
+		UsersRegistered,
+	}
+	for _, c := range append(cs, utility.BreakerCollectors()...) {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestNewRegistry(t *testing.T) {
+	mysqlDB, err := sql.Open("mysql", "user:pass@tcp(localhost:3306)/article")
+	assert.NoError(t, err)
+	postgresDB, err := gorm.Open(postgres.New(postgres.Config{Conn: mysqlDB}), &gorm.Config{DisableAutomaticPing: true})
+	assert.NoError(t, err)
+
+	registry, err := metrics.NewRegistry(db.Database{Mysql: mysqlDB, Postgres: postgresDB})
+	assert.NoError(t, err)
+
+	metrics.ArticlesCreated.Inc()
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	pools := map[string]bool{}
+	names := map[string]bool{}
+	for _, f := range families {
+		names[f.GetName()] = true
+		if f.GetName() == "go_sql_open_connections" {
+			for _, m := range f.GetMetric() {
+				pools[m.GetLabel()[0].GetValue()] = true
+			}
+		}
+	}
+	assert.True(t, names["articles_created_total"])
+	assert.True(t, names["go_goroutines"])
+	assert.Equal(t, map[string]bool{"mysql": true, "postgres": true}, pools)
+}
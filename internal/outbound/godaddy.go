@@ -0,0 +1,79 @@
+package outbound
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// GodaddyBreaker is the circuit breaker name of every GoDaddy call
+const GodaddyBreaker = "godaddy"
+
+// GodaddyOutbound represent the GoDaddy API contract
+type GodaddyOutbound interface {
+	GetDomainAvailable(ctx context.Context, domain string) (models.DomainAvailableResponse, error)
+}
+
+type godaddyOutbound struct {
+	host          string
+	authorization string
+	client        *utility.BreakerClient
+}
+
+// godaddyError is the body GoDaddy answers with on 4xx
+type godaddyError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewGodaddyOutbound will create an object that represent the GodaddyOutbound interface
+func NewGodaddyOutbound(config models.Config) GodaddyOutbound {
+	if config.Godaddy.Host == "" {
+		panic("Godaddy host is empty")
+	}
+	return &godaddyOutbound{
+		host:          strings.TrimRight(config.Godaddy.Host, "/"),
+		authorization: config.Godaddy.Authorization,
+		client:        utility.NewBreakerClient(GodaddyBreaker, config.Breakers[GodaddyBreaker]),
+	}
+}
+
+func (g *godaddyOutbound) GetDomainAvailable(ctx context.Context, domain string) (res models.DomainAvailableResponse, err error) {
+	query := url.Values{}
+	query.Set("domain", domain)
+	query.Set("checkType", "FAST")
+	query.Set("forTransfer", "false")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/domains/available?%s", g.host, query.Encode()), nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", g.authorization)
+
+	status, body, err := g.client.Do(ctx, req)
+	if err != nil {
+		return
+	}
+
+	if status != http.StatusOK {
+		// GoDaddy explains its 4xx with a code, the body may still be anything else
+		var gErr godaddyError
+		_ = json.Unmarshal(body, &gErr)
+		utility.Logger(ctx).Errorf("godaddy answered %d %s: %s", status, gErr.Code, gErr.Message)
+		if (status == http.StatusBadRequest || status == http.StatusUnprocessableEntity) &&
+			(strings.HasPrefix(gErr.Code, "INVALID") || gErr.Code == "UNSUPPORTED_TLD") {
+			return res, utility.ErrBadParamInput
+		}
+		return res, utility.ErrInternalServerError
+	}
+
+	err = json.Unmarshal(body, &res)
+	return
+}
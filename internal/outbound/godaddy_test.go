@@ -0,0 +1,84 @@
+package outbound_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kecci/goscription/internal/outbound"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGodaddy mimics GET /v1/domains/available of the GoDaddy API
+func fakeGodaddy(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/domains/available", r.URL.Path)
+		if r.Header.Get("Authorization") != "sso-key key:secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "UNABLE_TO_AUTHENTICATE", "message": "Unable to authenticate"})
+			return
+		}
+
+		domain := r.URL.Query().Get("domain")
+		w.Header().Set("Content-Type", "application/json")
+		switch domain {
+		case "forbidden.com":
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Access denied"})
+		case "invalid..com":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "INVALID_DOMAIN", "message": "Domain is invalid"})
+		default:
+			_ = json.NewEncoder(w).Encode(models.DomainAvailableResponse{
+				Available:  true,
+				Currency:   "USD",
+				Definitive: false,
+				Domain:     domain,
+				Period:     1,
+				Price:      11990000,
+			})
+		}
+	}))
+}
+
+func TestGetDomainAvailable(t *testing.T) {
+	server := fakeGodaddy(t)
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		g := outbound.NewGodaddyOutbound(models.Config{Godaddy: models.Godaddy{Host: server.URL, Authorization: "sso-key key:secret"}})
+
+		res, err := g.GetDomainAvailable(context.TODO(), "example.com")
+
+		assert.NoError(t, err)
+		assert.True(t, res.Available)
+		assert.Equal(t, "example.com", res.Domain)
+		assert.Equal(t, "USD", res.Currency)
+		assert.EqualValues(t, 11990000, res.Price)
+	})
+	t.Run("invalid-domain", func(t *testing.T) {
+		g := outbound.NewGodaddyOutbound(models.Config{Godaddy: models.Godaddy{Host: server.URL, Authorization: "sso-key key:secret"}})
+
+		_, err := g.GetDomainAvailable(context.TODO(), "invalid..com")
+
+		assert.Equal(t, utility.ErrBadParamInput, err)
+	})
+	t.Run("rejected-without-code", func(t *testing.T) {
+		g := outbound.NewGodaddyOutbound(models.Config{Godaddy: models.Godaddy{Host: server.URL, Authorization: "sso-key key:secret"}})
+
+		_, err := g.GetDomainAvailable(context.TODO(), "forbidden.com")
+
+		assert.Equal(t, utility.ErrInternalServerError, err)
+	})
+	t.Run("unauthorized", func(t *testing.T) {
+		g := outbound.NewGodaddyOutbound(models.Config{Godaddy: models.Godaddy{Host: server.URL, Authorization: "sso-key wrong"}})
+
+		_, err := g.GetDomainAvailable(context.TODO(), "example.com")
+
+		assert.Equal(t, utility.ErrInternalServerError, err)
+	})
+}
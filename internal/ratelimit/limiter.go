@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"go.uber.org/fx"
+)
+
+// Module for the rate limiter
+var Module = fx.Provide(NewLimiter)
+
+// defaultScope names the budget of the routes without a rule of their own
+const defaultScope = "default"
+
+// Limiter picks the rule of a route and spends from the bucket of a client
+type Limiter struct {
+	store        Store
+	apiKeyHeader string
+	defaultRule  Rule
+	routes       map[string]Rule
+}
+
+// NewLimiter will create the limiter of config.RateLimit, nil when it is disabled
+func NewLimiter(config models.Config, database db.Database) (*Limiter, error) {
+	if !config.RateLimit.Enabled {
+		return nil, nil
+	}
+
+	var store Store
+	switch config.RateLimit.Store {
+	case "", "memory":
+		store = NewMemoryStore()
+	case "mysql":
+		store = NewMySQLStore(database.Mysql)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", config.RateLimit.Store)
+	}
+
+	l := New(store, ruleOf(config.RateLimit.Default))
+	l.apiKeyHeader = config.RateLimit.APIKeyHeader
+	for _, r := range config.RateLimit.Routes {
+		l.Route(r.Method, r.Path, ruleOf(r.RateLimitRule))
+	}
+	return l, nil
+}
+
+// New will create a limiter applying defaultRule to every route
+func New(store Store, defaultRule Rule) *Limiter {
+	return &Limiter{
+		store:       store,
+		defaultRule: defaultRule,
+		routes:      map[string]Rule{},
+	}
+}
+
+// Route gives the route template its own rule and budget
+func (l *Limiter) Route(method, path string, rule Rule) {
+	l.routes[scope(method, path)] = rule
+}
+
+// APIKeyHeader is the header carrying the API key of the client, empty when keys are ignored
+func (l *Limiter) APIKeyHeader() string {
+	return l.apiKeyHeader
+}
+
+// Take spends a token of the client on the route, unlimited routes are always allowed
+func (l *Limiter) Take(ctx context.Context, method, path, client string) (rule Rule, res Result, err error) {
+	name := scope(method, path)
+	rule, ok := l.routes[name]
+	if !ok {
+		name, rule = defaultScope, l.defaultRule
+	}
+	if rule.Unlimited() {
+		return rule, Result{Allowed: true}, nil
+	}
+
+	res, err = l.store.Take(ctx, name+"|"+client, rule, time.Now())
+	return rule, res, err
+}
+
+func scope(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func ruleOf(r models.RateLimitRule) Rule {
+	return Rule{
+		Requests: r.Requests,
+		Period:   time.Duration(r.Period) * time.Second,
+		Burst:    r.Burst,
+	}
+}
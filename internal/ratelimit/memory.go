@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store forgets the buckets that refilled
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	full      time.Time
+}
+
+// MemoryStore keeps the buckets of a single replica
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore will create an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take ...
+func (s *MemoryStore) Take(ctx context.Context, key string, rule Rule, now time.Time) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: rule.capacity(), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	tokens, res := take(rule, b.tokens, b.updatedAt, now)
+	b.tokens, b.updatedAt, b.full = tokens, now, now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep drops the buckets full again, they are the same as a new one
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
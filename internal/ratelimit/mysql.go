@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/kecci/goscription/utility"
+)
+
+// MySQLStore keeps the buckets in the rate_limit_bucket table, so every replica
+// spends from the same budget. Each take locks the row of its bucket.
+type MySQLStore struct {
+	Conn      *sql.DB
+	mutex     sync.Mutex
+	lastSweep time.Time
+}
+
+// NewMySQLStore will create a store over the MySQL connection
+func NewMySQLStore(conn *sql.DB) *MySQLStore {
+	return &MySQLStore{Conn: conn}
+}
+
+// Take ...
+func (s *MySQLStore) Take(ctx context.Context, key string, rule Rule, now time.Time) (res Result, err error) {
+	s.sweep(ctx, now)
+
+	// the keys carry API keys, only their digest is stored
+	sum := sha256.Sum256([]byte(key))
+	id := hex.EncodeToString(sum[:])
+
+	tx, err := s.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				utility.Logger(ctx).Error(rbErr)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "INSERT IGNORE INTO `rate_limit_bucket` (`key`, tokens, updated_at, full_at) VALUES (?, ?, ?, ?)",
+		id, rule.capacity(), now, now)
+	if err != nil {
+		return
+	}
+
+	var (
+		tokens    float64
+		updatedAt time.Time
+	)
+	err = tx.QueryRowContext(ctx, "SELECT tokens, updated_at FROM `rate_limit_bucket` WHERE `key` = ? FOR UPDATE", id).
+		Scan(&tokens, &updatedAt)
+	if err != nil {
+		return
+	}
+
+	tokens, res = take(rule, tokens, updatedAt, now)
+	_, err = tx.ExecContext(ctx, "UPDATE `rate_limit_bucket` SET tokens = ?, updated_at = ?, full_at = ? WHERE `key` = ?",
+		tokens, now, now.Add(res.Reset), id)
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// sweep deletes the buckets full again once in a while, a failure only delays it
+func (s *MySQLStore) sweep(ctx context.Context, now time.Time) {
+	s.mutex.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mutex.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mutex.Unlock()
+
+	if _, err := s.Conn.ExecContext(ctx, "DELETE FROM `rate_limit_bucket` WHERE full_at <= ?", now); err != nil {
+		utility.Logger(ctx).Error(err)
+	}
+}
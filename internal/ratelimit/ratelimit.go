@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule is a token bucket: Burst requests at once, refilled at Requests per Period
+type Rule struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Unlimited tells the rule lets every request through
+func (r Rule) Unlimited() bool {
+	return r.Requests <= 0 || r.Period <= 0
+}
+
+// rate is the refill in tokens per second
+func (r Rule) rate() float64 {
+	return float64(r.Requests) / r.Period.Seconds()
+}
+
+func (r Rule) capacity() float64 {
+	if r.Burst <= 0 {
+		return float64(r.Requests)
+	}
+	return float64(r.Burst)
+}
+
+// Result is the answer of a store to one request
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. A store shared by every replica, such as the MySQL one,
+// makes them enforce a single budget per client.
+type Store interface {
+	// Take spends a token of the bucket of key, refilled up to now
+	Take(ctx context.Context, key string, rule Rule, now time.Time) (Result, error)
+}
+
+// take refills a bucket holding tokens since updatedAt and spends one token when it can,
+// it returns the tokens left along with the result
+func take(rule Rule, tokens float64, updatedAt, now time.Time) (float64, Result) {
+	capacity := rule.capacity()
+	if elapsed := now.Sub(updatedAt).Seconds(); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed*rule.rate())
+	}
+
+	res := Result{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rule.rate())
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = seconds((capacity - tokens) / rule.rate())
+	return tokens, res
+}
+
+// seconds rounds up to the second the headers are expressed in
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
@@ -0,0 +1,56 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kecci/goscription/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	rule := ratelimit.Rule{Requests: 6, Period: time.Minute, Burst: 2}
+	now := time.Now()
+
+	res, err := s.Take(context.TODO(), "ip:10.0.0.1", rule, now)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, 10*time.Second, res.Reset)
+
+	res, _ = s.Take(context.TODO(), "ip:10.0.0.1", rule, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, _ = s.Take(context.TODO(), "ip:10.0.0.1", rule, now.Add(time.Second))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 9*time.Second, res.RetryAfter)
+
+	// another client has its own bucket
+	res, _ = s.Take(context.TODO(), "ip:10.0.0.2", rule, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+
+	// one token is back every 10 seconds
+	res, _ = s.Take(context.TODO(), "ip:10.0.0.1", rule, now.Add(10*time.Second))
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestLimiter(t *testing.T) {
+	l := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Rule{})
+	l.Route("post", "/articles", ratelimit.Rule{Requests: 1, Period: time.Hour})
+
+	_, res, err := l.Take(context.TODO(), "GET", "/articles", "user:1")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	_, res, _ = l.Take(context.TODO(), "POST", "/articles", "user:1")
+	assert.True(t, res.Allowed)
+	rule, res, _ := l.Take(context.TODO(), "POST", "/articles", "user:1")
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Hour, rule.Period)
+	assert.Equal(t, time.Hour, res.RetryAfter)
+}
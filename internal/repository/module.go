@@ -11,6 +11,11 @@ var Module = fx.Options(
 	fx.Provide(
 		mysql.NewArticleRepository,
 		mysql.NewUserRepository,
+		mysql.NewRefreshTokenRepository,
+		mysql.NewRoleRepository,
+		mysql.NewUserTokenRepository,
+		mysql.NewTagRepository,
+		mysql.NewArticleRevisionRepository,
 		postgres.NewAddressRepository,
 	),
 )
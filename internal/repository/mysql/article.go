@@ -3,25 +3,33 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/kecci/goscription/internal/library/db"
 	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
-	"github.com/sirupsen/logrus"
 )
 
-// ArticleRepository represent the repository contract
+// ArticleRepository represent the repository contract. The tags of an article are
+// read and written along with it, a nil Tags leaves the stored ones untouched. Deleted
+// articles go to the trash, only Fetch on the trash and GetTrashedByID return them.
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []models.Article, csr string, err error)
+	Fetch(ctx context.Context, filter models.ArticleFilter, cursor string, num int64) (res []models.Article, csr string, err error)
+	Search(ctx context.Context, q string, cursor string, num int64) (res []models.ArticleSearchResult, csr string, err error)
 	GetByID(ctx context.Context, id int64) (res models.Article, err error)
 	GetByTitle(ctx context.Context, title string) (res models.Article, err error)
-	Update(ctx context.Context, article *models.Article) (err error)
+	Update(ctx context.Context, article *models.Article, editorID int64) (err error)
 	Store(ctx context.Context, a *models.Article) (err error)
 	Delete(ctx context.Context, id int64) (err error)
+	PublishDue(ctx context.Context, now time.Time) (published int64, err error)
+	GetTrashedByID(ctx context.Context, id int64) (res models.Article, err error)
+	RestoreTrashed(ctx context.Context, id int64) (err error)
+	Purge(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
 }
 
 type mysqlArticleRepository struct {
@@ -36,44 +44,129 @@ func NewArticleRepository(DB db.Database) ArticleRepository {
 	return &mysqlArticleRepository{DB.Mysql}
 }
 
+// articleColumns are the columns scanned by fetch, out of the article joined with its author
+var articleColumns = []string{"a.id", "a.title", "a.content", "a.version", "a.status", "a.publish_at", "a.updated_at", "a.created_at", "a.deleted_at", "a.author_id", "u.name"}
+
+// selectArticles starts a query on the articles joined with their author, trashed ones included
+func selectArticles() squirrel.SelectBuilder {
+	return squirrel.Select(articleColumns...).From("article a").LeftJoin("user u ON u.id = a.author_id")
+}
+
+// notTrashed filters out the articles in the trash
+const notTrashed = "a.deleted_at IS NULL"
+
+// articleAuthor scans the nullable author columns
+type articleAuthor struct {
+	ID   sql.NullInt64
+	Name sql.NullString
+}
+
+func (a articleAuthor) summary() *models.ArticleAuthor {
+	if !a.ID.Valid {
+		return nil
+	}
+	return &models.ArticleAuthor{ID: a.ID.Int64, Name: a.Name.String}
+}
+
 func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.Article, err error) {
 	rows, err := m.Conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		utility.Logger(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			utility.Logger(ctx).Error(err)
 		}
 	}()
 
 	result = make([]models.Article, 0)
 	for rows.Next() {
 		t := models.Article{}
+		var author articleAuthor
 		err = rows.Scan(
 			&t.ID,
 			&t.Title,
 			&t.Content,
+			&t.Version,
+			&t.Status,
+			&t.PublishAt,
 			&t.UpdatedAt,
 			&t.CreatedAt,
+			&t.DeletedAt,
+			&author.ID,
+			&author.Name,
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			utility.Logger(ctx).Error(err)
 			return nil, err
 		}
+		t.Author = author.summary()
 		result = append(result, t)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	// release the connection before asking for the tags
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(result))
+	for i := range result {
+		ids[i] = result[i].ID
+	}
+	tags, err := articleTags(ctx, m.Conn, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].Tags = tagsOrEmpty(tags[result[i].ID])
+	}
 
 	return result, nil
 }
 
-func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []models.Article, nextCursor string, err error) {
-	qbuilder := squirrel.Select("id", "title", "content", "updated_at", "created_at").From("article")
-	qbuilder = qbuilder.OrderBy("id DESC").Limit(uint64(num))
+// tagsOrEmpty keeps untagged articles rendering an empty list rather than null
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// Fetch lists the articles newest first. Filtering on tags keeps the articles carrying all of them.
+func (m *mysqlArticleRepository) Fetch(ctx context.Context, filter models.ArticleFilter, cursor string, num int64) (res []models.Article, nextCursor string, err error) {
+	qbuilder := selectArticles()
+	qbuilder = qbuilder.OrderBy("a.id DESC").Limit(uint64(num))
+
+	if filter.Trashed {
+		qbuilder = qbuilder.Where("a.deleted_at IS NOT NULL")
+	} else {
+		qbuilder = qbuilder.Where(notTrashed)
+	}
+	if filter.AuthorID != 0 {
+		qbuilder = qbuilder.Where(squirrel.Eq{"a.author_id": filter.AuthorID})
+	}
+	if filter.Status != "" {
+		qbuilder = qbuilder.Where(squirrel.Eq{"a.status": filter.Status})
+	}
+	if len(filter.Tags) > 0 {
+		tagged, args, err := squirrel.Select("at.article_id").
+			From("article_tag at").
+			Join("tag t ON t.id = at.tag_id").
+			Where(squirrel.Eq{"t.name": filter.Tags}).
+			GroupBy("at.article_id").
+			Having("COUNT(DISTINCT t.id) = ?", len(filter.Tags)).
+			ToSql()
+		if err != nil {
+			return nil, "", err
+		}
+		qbuilder = qbuilder.Where("a.id IN ("+tagged+")", args...)
+	}
 
 	if cursor != "" {
 		decodedCursor, err := strconv.ParseInt(cursor, 10, 64)
@@ -81,7 +174,7 @@ func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num i
 			return nil, "", utility.ErrBadParamInput
 		}
 		qbuilder = qbuilder.Where(squirrel.Lt{
-			"id": decodedCursor,
+			"a.id": decodedCursor,
 		})
 	}
 
@@ -102,52 +195,186 @@ func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num i
 	return
 }
 
-func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res models.Article, err error) {
-	query := `SELECT id,title,content, updated_at, created_at
-  						FROM article WHERE ID = ?`
+// articleRelevance ranks an article against a search query, rounded so that the score handed
+// out in a cursor compares equal to the one computed again on the next page
+const articleRelevance = "ROUND(MATCH(a.title, a.content) AGAINST (? IN NATURAL LANGUAGE MODE), 6)"
+
+// Search ranks the published articles matching q by relevance. The cursor carries the score and
+// the id of the last article of the page, the id breaks ties between equal scores.
+func (m *mysqlArticleRepository) Search(ctx context.Context, q string, cursor string, num int64) (res []models.ArticleSearchResult, nextCursor string, err error) {
+	qbuilder := selectArticles().
+		Column(squirrel.Alias(squirrel.Expr(articleRelevance, q), "score")).
+		Where(notTrashed).
+		Where(squirrel.Expr(articleRelevance+" > 0", q)).
+		Where(squirrel.Eq{"a.status": models.ArticleStatusPublished})
+	qbuilder = qbuilder.OrderBy("score DESC", "a.id DESC").Limit(uint64(num))
+
+	if cursor != "" {
+		score, id, err := decodeSearchCursor(cursor)
+		if err != nil {
+			return nil, "", utility.ErrBadParamInput
+		}
+		qbuilder = qbuilder.Where(squirrel.Or{
+			squirrel.Expr(articleRelevance+" < ?", q, score),
+			squirrel.And{
+				squirrel.Expr(articleRelevance+" = ?", q, score),
+				squirrel.Lt{"a.id": id},
+			},
+		})
+	}
 
-	list, err := m.fetch(ctx, query, id)
+	query, args, err := qbuilder.ToSql()
 	if err != nil {
-		return models.Article{}, err
+		return
 	}
 
-	if len(list) > 0 {
-		res = list[0]
-	} else {
-		return res, utility.ErrNotFound
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, "", err
 	}
 
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	res = make([]models.ArticleSearchResult, 0)
+	for rows.Next() {
+		t := models.ArticleSearchResult{}
+		var author articleAuthor
+		err = rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Content,
+			&t.Version,
+			&t.Status,
+			&t.PublishAt,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+			&t.DeletedAt,
+			&author.ID,
+			&author.Name,
+			&t.Score,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, "", err
+		}
+		t.Author = author.summary()
+		res = append(res, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	if err = rows.Close(); err != nil {
+		return nil, "", err
+	}
+
+	ids := make([]int64, len(res))
+	for i := range res {
+		ids[i] = res[i].ID
+	}
+	tags, err := articleTags(ctx, m.Conn, ids)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range res {
+		res[i].Tags = tagsOrEmpty(tags[res[i].ID])
+	}
+
+	nextCursor = cursor
+	if len(res) > 0 {
+		last := res[len(res)-1]
+		nextCursor = encodeSearchCursor(last.Score, last.ID)
+	}
 	return
 }
 
+func encodeSearchCursor(score float64, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatFloat(score, 'f', 6, 64) + ":" + strconv.FormatInt(id, 10)))
+}
+
+func decodeSearchCursor(cursor string) (score float64, id int64, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, err
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed search cursor %q", cursor)
+	}
+	if score, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return 0, 0, err
+	}
+	if id, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return score, id, nil
+}
+
+func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res models.Article, err error) {
+	return m.getOne(ctx, selectArticles().Where(notTrashed).Where(squirrel.Eq{"a.id": id}))
+}
+
 func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (res models.Article, err error) {
-	query := `SELECT id,title,content, updated_at, created_at
-  						FROM article WHERE title = ?`
+	return m.getOne(ctx, selectArticles().Where(notTrashed).Where(squirrel.Eq{"a.title": title}))
+}
+
+// GetTrashedByID finds an article in the trash
+func (m *mysqlArticleRepository) GetTrashedByID(ctx context.Context, id int64) (res models.Article, err error) {
+	return m.getOne(ctx, selectArticles().Where("a.deleted_at IS NOT NULL").Where(squirrel.Eq{"a.id": id}))
+}
 
-	list, err := m.fetch(ctx, query, title)
+func (m *mysqlArticleRepository) getOne(ctx context.Context, qbuilder squirrel.SelectBuilder) (res models.Article, err error) {
+	query, args, err := qbuilder.ToSql()
 	if err != nil {
 		return
 	}
 
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return models.Article{}, err
+	}
+
 	if len(list) > 0 {
 		res = list[0]
 	} else {
 		return res, utility.ErrNotFound
 	}
+
 	return
 }
 
 func (m *mysqlArticleRepository) Store(ctx context.Context, a *models.Article) (err error) {
-	query := `INSERT  article SET title=? , content=? , updated_at=? , created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				utility.Logger(ctx).Error(rbErr)
+			}
+		}
+	}()
+
+	query := `INSERT  article SET title=? , content=? , status=? , publish_at=? , author_id=? , version=1 , updated_at=? , created_at=?`
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
+	var authorID sql.NullInt64
+	if a.Author != nil {
+		authorID = sql.NullInt64{Int64: a.Author.ID, Valid: true}
+	}
+
 	now := time.Now()
-	a.CreatedAt = now
-	a.UpdatedAt = now
-	res, err := stmt.ExecContext(ctx, a.Title, a.Content, now, now)
+	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Status, a.PublishAt, authorID, now, now)
 	if err != nil {
 		return
 	}
@@ -155,19 +382,31 @@ func (m *mysqlArticleRepository) Store(ctx context.Context, a *models.Article) (
 	if err != nil {
 		return
 	}
+	if err = setArticleTags(ctx, tx, lastID, a.Tags); err != nil {
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
 	a.ID = lastID
+	a.Version = 1
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	a.Tags = tagsOrEmpty(a.Tags)
 	return
 }
 
+// Delete moves the article to the trash, its tags and revisions stay until it is purged
 func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM article WHERE id = ?"
+	query := "UPDATE article SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL"
 
 	stmt, err := m.Conn.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, id)
+	res, err := stmt.ExecContext(ctx, time.Now(), id)
 	if err != nil {
 		return
 	}
@@ -177,24 +416,111 @@ func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) (err erro
 		return
 	}
 
-	if rowsAfected != 1 {
-		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", rowsAfected)
+	if rowsAfected == 0 {
+		return utility.ErrNotFound
+	}
+	return
+}
+
+// RestoreTrashed takes the article back out of the trash
+func (m *mysqlArticleRepository) RestoreTrashed(ctx context.Context, id int64) (err error) {
+	query := "UPDATE article SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL"
+
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
 		return
 	}
 
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected == 0 {
+		return utility.ErrNotFound
+	}
 	return
 }
-func (m *mysqlArticleRepository) Update(ctx context.Context, ar *models.Article) (err error) {
-	query := `UPDATE article set title=?, content=?, updated_at=? WHERE ID = ?`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+// Purge permanently removes the articles trashed before deletedBefore, along with their
+// tags and revisions
+func (m *mysqlArticleRepository) Purge(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	tx, err := m.Conn.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				utility.Logger(ctx).Error(rbErr)
+			}
+		}
+	}()
+
+	queries := []string{
+		`DELETE at FROM article_tag at JOIN article a ON a.id = at.article_id WHERE a.deleted_at < ?`,
+		`DELETE r FROM article_revision r JOIN article a ON a.id = r.article_id WHERE a.deleted_at < ?`,
+	}
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, deletedBefore); err != nil {
+			return
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM article WHERE deleted_at < ?`, deletedBefore)
+	if err != nil {
+		return
+	}
+	if purged, err = res.RowsAffected(); err != nil {
+		return
+	}
+	return purged, tx.Commit()
+}
+
+// Update only succeeds when ar.Version still matches the stored row, and bumps the version on success.
+// The replaced state is kept as a revision edited by editorID, within the same transaction.
+func (m *mysqlArticleRepository) Update(ctx context.Context, ar *models.Article, editorID int64) (err error) {
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				utility.Logger(ctx).Error(rbErr)
+			}
+		}
+	}()
+
+	// the row stays locked until the commit, a concurrent update of the same version
+	// waits here and then finds the version bumped
+	old := models.Article{ID: ar.ID}
+	err = tx.QueryRowContext(ctx, `SELECT version, title, content FROM article WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, ar.ID).
+		Scan(&old.Version, &old.Title, &old.Content)
+	if err == sql.ErrNoRows {
+		return utility.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+	if old.Version != ar.Version {
+		return utility.ErrVersionConflict
+	}
 
 	now := time.Now()
-	ar.UpdatedAt = now
-	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, now, ar.ID)
+	query := `UPDATE article set title=?, content=?, status=?, publish_at=?, version=version+1, updated_at=? WHERE ID = ? AND version = ? AND deleted_at IS NULL`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Status, ar.PublishAt, now, ar.ID, ar.Version)
 	if err != nil {
 		return
 	}
@@ -209,5 +535,35 @@ func (m *mysqlArticleRepository) Update(ctx context.Context, ar *models.Article)
 		return
 	}
 
+	if err = storeRevision(ctx, tx, old, editorID, now); err != nil {
+		return
+	}
+	if ar.Tags != nil {
+		if err = setArticleTags(ctx, tx, ar.ID, ar.Tags); err != nil {
+			return
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	ar.UpdatedAt = now
+	ar.Version++
 	return
 }
+
+// PublishDue publishes the scheduled articles whose publish_at has passed. The version is
+// left alone, the content did not change.
+func (m *mysqlArticleRepository) PublishDue(ctx context.Context, now time.Time) (published int64, err error) {
+	query := `UPDATE article SET status=? WHERE status=? AND publish_at <= ? AND deleted_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, models.ArticleStatusPublished, models.ArticleStatusScheduled, now)
+	if err != nil {
+		return
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// ArticleRevisionRepository represent the repository contract. Revisions are only ever
+// appended, by ArticleRepository.Update in the transaction of the update itself.
+type ArticleRevisionRepository interface {
+	Fetch(ctx context.Context, articleID int64, cursor string, num int64) (res []models.ArticleRevision, csr string, err error)
+	GetByVersion(ctx context.Context, articleID, version int64) (res models.ArticleRevision, err error)
+}
+
+type mysqlArticleRevisionRepository struct {
+	Conn *sql.DB
+}
+
+// NewArticleRevisionRepository will create an object that represent the ArticleRevisionRepository interface
+func NewArticleRevisionRepository(DB db.Database) ArticleRevisionRepository {
+	if DB.Mysql == nil {
+		panic("Database Connections is nil")
+	}
+	return &mysqlArticleRevisionRepository{DB.Mysql}
+}
+
+// selectRevisions starts a query on the revisions joined with their editor
+func selectRevisions() squirrel.SelectBuilder {
+	return squirrel.Select("r.id", "r.article_id", "r.version", "r.title", "r.content", "r.edited_by", "u.name", "r.edited_at").
+		From("article_revision r").
+		LeftJoin("user u ON u.id = r.edited_by")
+}
+
+func (m *mysqlArticleRevisionRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.ArticleRevision, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	result = make([]models.ArticleRevision, 0)
+	for rows.Next() {
+		t := models.ArticleRevision{}
+		var editor articleAuthor
+		err = rows.Scan(
+			&t.ID,
+			&t.ArticleID,
+			&t.Version,
+			&t.Title,
+			&t.Content,
+			&editor.ID,
+			&editor.Name,
+			&t.EditedAt,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		t.EditedBy = editor.summary()
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch lists the revisions of an article, newest first. The cursor is the last version handed out.
+func (m *mysqlArticleRevisionRepository) Fetch(ctx context.Context, articleID int64, cursor string, num int64) (res []models.ArticleRevision, nextCursor string, err error) {
+	qbuilder := selectRevisions().Where(squirrel.Eq{"r.article_id": articleID})
+	qbuilder = qbuilder.OrderBy("r.version DESC").Limit(uint64(num))
+
+	if cursor != "" {
+		decodedCursor, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, "", utility.ErrBadParamInput
+		}
+		qbuilder = qbuilder.Where(squirrel.Lt{
+			"r.version": decodedCursor,
+		})
+	}
+
+	query, args, err := qbuilder.ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err = m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor = cursor
+	if len(res) > 0 {
+		nextCursor = fmt.Sprintf("%d", res[len(res)-1].Version)
+	}
+	return
+}
+
+func (m *mysqlArticleRevisionRepository) GetByVersion(ctx context.Context, articleID, version int64) (res models.ArticleRevision, err error) {
+	query, args, err := selectRevisions().Where(squirrel.Eq{"r.article_id": articleID, "r.version": version}).ToSql()
+	if err != nil {
+		return
+	}
+
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return models.ArticleRevision{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, utility.ErrNotFound
+	}
+	return
+}
+
+// storeRevision keeps the state an update is about to replace, read from the locked row
+func storeRevision(ctx context.Context, q queryer, old models.Article, editorID int64, editedAt time.Time) (err error) {
+	var editedBy sql.NullInt64
+	if editorID != 0 {
+		editedBy = sql.NullInt64{Int64: editorID, Valid: true}
+	}
+
+	query := `INSERT INTO article_revision (article_id, version, title, content, edited_by, edited_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = q.ExecContext(ctx, query, old.ID, old.Version, old.Title, old.Content, editedBy, editedAt)
+	return
+}
@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// RefreshTokenRepository represent the repository contract
+type RefreshTokenRepository interface {
+	Store(ctx context.Context, t *models.RefreshToken) (err error)
+	GetByHash(ctx context.Context, hash string) (res models.RefreshToken, err error)
+	Revoke(ctx context.Context, id int64) (err error)
+	RevokeByUser(ctx context.Context, userID int64) (err error)
+}
+
+type mysqlRefreshTokenRepository struct {
+	Conn *sql.DB
+}
+
+// NewRefreshTokenRepository will create an object that represent the RefreshTokenRepository interface
+func NewRefreshTokenRepository(DB db.Database) RefreshTokenRepository {
+	if DB.Mysql == nil {
+		panic("Database Connections is nil")
+	}
+	return &mysqlRefreshTokenRepository{DB.Mysql}
+}
+
+func (m *mysqlRefreshTokenRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.RefreshToken, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	result = make([]models.RefreshToken, 0)
+	for rows.Next() {
+		t := models.RefreshToken{}
+		err = rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.TokenHash,
+			&t.ExpiresAt,
+			&t.RevokedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlRefreshTokenRepository) Store(ctx context.Context, t *models.RefreshToken) (err error) {
+	query := `INSERT refresh_token SET user_id=?, token_hash=?, expires_at=?, created_at=?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	t.CreatedAt = time.Now()
+	res, err := stmt.ExecContext(ctx, t.UserID, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	t.ID = lastID
+	return
+}
+
+func (m *mysqlRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (res models.RefreshToken, err error) {
+	query := `SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+  						FROM refresh_token WHERE token_hash = ?`
+
+	list, err := m.fetch(ctx, query, hash)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, utility.ErrNotFound
+	}
+	return
+}
+
+// Revoke marks a live token as revoked, a token that is already revoked yields ErrNotFound
+// so that two concurrent refreshes cannot both rotate the same token.
+func (m *mysqlRefreshTokenRepository) Revoke(ctx context.Context, id int64) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=? WHERE id = ? AND revoked_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now(), id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		return utility.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=? WHERE user_id = ? AND revoked_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, time.Now(), userID)
+	return
+}
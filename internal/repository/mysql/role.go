@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// RoleRepository represent the repository contract
+type RoleRepository interface {
+	Fetch(ctx context.Context) (res []models.Role, err error)
+	GetByUser(ctx context.Context, userID int64) (res []models.Role, err error)
+	GetPermissionsByUser(ctx context.Context, userID int64) (res []string, err error)
+	AssignRole(ctx context.Context, userID int64, role string) (err error)
+	RevokeRole(ctx context.Context, userID int64, role string) (err error)
+}
+
+type mysqlRoleRepository struct {
+	Conn *sql.DB
+}
+
+// NewRoleRepository will create an object that represent the RoleRepository interface
+func NewRoleRepository(DB db.Database) RoleRepository {
+	if DB.Mysql == nil {
+		panic("Database Connections is nil")
+	}
+	return &mysqlRoleRepository{DB.Mysql}
+}
+
+// fetch folds the (role, permission) rows of query into roles, keeping the row order
+func (m *mysqlRoleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.Role, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	result = make([]models.Role, 0)
+	index := map[int64]int{}
+	for rows.Next() {
+		var (
+			r          models.Role
+			permission sql.NullString
+		)
+		err = rows.Scan(
+			&r.ID,
+			&r.Name,
+			&permission,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+
+		i, ok := index[r.ID]
+		if !ok {
+			r.Permissions = make([]string, 0)
+			result = append(result, r)
+			i = len(result) - 1
+			index[r.ID] = i
+		}
+		if permission.Valid {
+			result[i].Permissions = append(result[i].Permissions, permission.String)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *mysqlRoleRepository) Fetch(ctx context.Context) (res []models.Role, err error) {
+	query := `SELECT r.id, r.name, p.name FROM role r
+  						LEFT JOIN role_permission rp ON rp.role_id = r.id
+  						LEFT JOIN permission p ON p.id = rp.permission_id
+  						ORDER BY r.id, p.id`
+
+	return m.fetch(ctx, query)
+}
+
+func (m *mysqlRoleRepository) GetByUser(ctx context.Context, userID int64) (res []models.Role, err error) {
+	query := `SELECT r.id, r.name, p.name FROM user_role ur
+  						JOIN role r ON r.id = ur.role_id
+  						LEFT JOIN role_permission rp ON rp.role_id = r.id
+  						LEFT JOIN permission p ON p.id = rp.permission_id
+  						WHERE ur.user_id = ?
+  						ORDER BY r.id, p.id`
+
+	return m.fetch(ctx, query, userID)
+}
+
+func (m *mysqlRoleRepository) GetPermissionsByUser(ctx context.Context, userID int64) (res []string, err error) {
+	query := `SELECT DISTINCT p.name FROM user_role ur
+  						JOIN role_permission rp ON rp.role_id = ur.role_id
+  						JOIN permission p ON p.id = rp.permission_id
+  						WHERE ur.user_id = ?
+  						ORDER BY p.name`
+
+	rows, err := m.Conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	res = make([]string, 0)
+	for rows.Next() {
+		var permission string
+		if err = rows.Scan(&permission); err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, permission)
+	}
+
+	return res, nil
+}
+
+// AssignRole grants role to a user, granting a role twice is a no-op
+func (m *mysqlRoleRepository) AssignRole(ctx context.Context, userID int64, role string) (err error) {
+	return assignRole(ctx, m.Conn, userID, role)
+}
+
+func (m *mysqlRoleRepository) RevokeRole(ctx context.Context, userID int64, role string) (err error) {
+	roleID, err := m.getRoleID(ctx, role)
+	if err != nil {
+		return
+	}
+
+	query := `DELETE FROM user_role WHERE user_id = ? AND role_id = ?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, roleID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		return utility.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlRoleRepository) getRoleID(ctx context.Context, role string) (id int64, err error) {
+	err = m.Conn.QueryRowContext(ctx, `SELECT id FROM role WHERE name = ?`, role).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, utility.ErrNotFound
+	}
+	return
+}
+
+// assignRole grants role to a user through q, so that it can join the transaction of the caller
+func assignRole(ctx context.Context, q queryer, userID int64, role string) (err error) {
+	var roleID int64
+	err = q.QueryRowContext(ctx, `SELECT id FROM role WHERE name = ?`, role).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		return utility.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	_, err = q.ExecContext(ctx, `INSERT IGNORE user_role SET user_id=?, role_id=?`, userID, roleID)
+	return
+}
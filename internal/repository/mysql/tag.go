@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// TagRepository represent the repository contract. Tags are written together with
+// their article, see ArticleRepository.
+type TagRepository interface {
+	Fetch(ctx context.Context) (res []models.Tag, err error)
+}
+
+type mysqlTagRepository struct {
+	Conn *sql.DB
+}
+
+// NewTagRepository will create an object that represent the TagRepository interface
+func NewTagRepository(DB db.Database) TagRepository {
+	if DB.Mysql == nil {
+		panic("Database Connections is nil")
+	}
+	return &mysqlTagRepository{DB.Mysql}
+}
+
+// Fetch lists the tags of the published articles out of the trash, the most used first
+func (m *mysqlTagRepository) Fetch(ctx context.Context) (res []models.Tag, err error) {
+	query := `SELECT t.id, t.name, COUNT(at.article_id) AS article_count
+  						FROM tag t JOIN article_tag at ON at.tag_id = t.id
+  						JOIN article a ON a.id = at.article_id AND a.status = ? AND a.deleted_at IS NULL
+  						GROUP BY t.id, t.name ORDER BY article_count DESC, t.name`
+
+	rows, err := m.Conn.QueryContext(ctx, query, models.ArticleStatusPublished)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	res = make([]models.Tag, 0)
+	for rows.Next() {
+		t := models.Tag{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.ArticleCount,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return res, rows.Err()
+}
+
+// queryer is what both *sql.DB and *sql.Tx offer
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// articleTags loads the tag names of the given articles, sorted by name
+func articleTags(ctx context.Context, q queryer, articleIDs []int64) (res map[int64][]string, err error) {
+	res = map[int64][]string{}
+	if len(articleIDs) == 0 {
+		return res, nil
+	}
+
+	query, args, err := squirrel.Select("at.article_id", "t.name").
+		From("article_tag at").
+		Join("tag t ON t.id = at.tag_id").
+		Where(squirrel.Eq{"at.article_id": articleIDs}).
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			articleID int64
+			name      string
+		)
+		if err = rows.Scan(&articleID, &name); err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		res[articleID] = append(res[articleID], name)
+	}
+
+	return res, rows.Err()
+}
+
+// setArticleTags replaces the tags of an article, creating the tags that do not exist yet
+func setArticleTags(ctx context.Context, q queryer, articleID int64, names []string) (err error) {
+	if _, err = q.ExecContext(ctx, `DELETE FROM article_tag WHERE article_id = ?`, articleID); err != nil {
+		return
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	insertTags := squirrel.Insert("tag").Columns("name").Suffix("ON DUPLICATE KEY UPDATE id = id")
+	for _, name := range names {
+		insertTags = insertTags.Values(name)
+	}
+	query, args, err := insertTags.ToSql()
+	if err != nil {
+		return
+	}
+	if _, err = q.ExecContext(ctx, query, args...); err != nil {
+		return
+	}
+
+	query, args, err = squirrel.Insert("article_tag").Columns("article_id", "tag_id").
+		Select(squirrel.Select("?", "id").From("tag").Where(squirrel.Eq{"name": names})).
+		ToSql()
+	if err != nil {
+		return
+	}
+	_, err = q.ExecContext(ctx, query, append([]interface{}{articleID}, args...)...)
+	return
+}
@@ -3,21 +3,28 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/kecci/goscription/internal/library/db"
 	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
-	"github.com/sirupsen/logrus"
 )
 
-// UserRepository represent the repository contract
+// UserRepository represent the repository contract. Deleted users are kept in the
+// table but none of the reads return them.
 type UserRepository interface {
-	Store(ctx context.Context, a *models.User) (err error)
-	// Get(ctx context.Context, cursor string, num int64) (res []models.Article, csr string, err error)
+	Store(ctx context.Context, a *models.User, role string) (err error)
+	Fetch(ctx context.Context, filter models.UserFilter, cursor string, num int64) (res []models.User, csr string, err error)
 	GetByID(ctx context.Context, id int64) (res models.User, err error)
 	GetByEmail(ctx context.Context, email string) (res models.User, err error)
-	// Update(ctx context.Context, article *models.Article) (err error)
-	// Delete(ctx context.Context, id int64) (err error)
+	Update(ctx context.Context, u *models.User) (err error)
+	UpdatePassword(ctx context.Context, id int64, hash string) (err error)
+	Delete(ctx context.Context, id int64) (err error)
+	MarkEmailVerified(ctx context.Context, id int64) (err error)
 }
 
 type mysqlUserRepository struct {
@@ -32,9 +39,22 @@ func NewUserRepository(DB db.Database) UserRepository {
 	return &mysqlUserRepository{DB.Mysql}
 }
 
-func (m *mysqlUserRepository) Store(ctx context.Context, a *models.User) (err error) {
+// Store inserts the user along with its first role, neither is kept without the other
+func (m *mysqlUserRepository) Store(ctx context.Context, a *models.User, role string) (err error) {
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				utility.Logger(ctx).Error(rbErr)
+			}
+		}
+	}()
+
 	query := `INSERT user SET name=?, email=?, password=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -47,12 +67,19 @@ func (m *mysqlUserRepository) Store(ctx context.Context, a *models.User) (err er
 	if err != nil {
 		return
 	}
+
+	if err = assignRole(ctx, tx, lastID, role); err != nil {
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		return
+	}
 	a.ID = lastID
 	return
 }
 
 func (m *mysqlUserRepository) GetByID(ctx context.Context, id int64) (res models.User, err error) {
-	query := `SELECT * FROM user WHERE ID = ?`
+	query := `SELECT id, name, email, password, email_verified_at FROM user WHERE ID = ? AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, id)
 	if err != nil {
@@ -68,17 +95,60 @@ func (m *mysqlUserRepository) GetByID(ctx context.Context, id int64) (res models
 	return
 }
 
+// Fetch filters on a part of the email or the name
+func (m *mysqlUserRepository) Fetch(ctx context.Context, filter models.UserFilter, cursor string, num int64) (res []models.User, nextCursor string, err error) {
+	qbuilder := squirrel.Select("id", "name", "email", "password", "email_verified_at").From("user").Where("deleted_at IS NULL")
+	qbuilder = qbuilder.OrderBy("id DESC").Limit(uint64(num))
+
+	if filter.Email != "" {
+		qbuilder = qbuilder.Where(squirrel.Like{"email": "%" + escapeLike(filter.Email) + "%"})
+	}
+	if filter.Name != "" {
+		qbuilder = qbuilder.Where(squirrel.Like{"name": "%" + escapeLike(filter.Name) + "%"})
+	}
+	if cursor != "" {
+		decodedCursor, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, "", utility.ErrBadParamInput
+		}
+		qbuilder = qbuilder.Where(squirrel.Lt{
+			"id": decodedCursor,
+		})
+	}
+
+	query, args, err := qbuilder.ToSql()
+	if err != nil {
+		return
+	}
+
+	res, err = m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor = cursor
+	if len(res) > 0 {
+		nextCursor = fmt.Sprintf("%d", res[len(res)-1].ID)
+	}
+	return
+}
+
+// escapeLike makes the LIKE wildcards of s match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.User, err error) {
 	rows, err := m.Conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		utility.Logger(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			utility.Logger(ctx).Error(err)
 		}
 	}()
 
@@ -90,10 +160,11 @@ func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...i
 			&t.Name,
 			&t.Email,
 			&t.Password,
+			&t.EmailVerifiedAt,
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			utility.Logger(ctx).Error(err)
 			return nil, err
 		}
 		result = append(result, t)
@@ -103,7 +174,7 @@ func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...i
 }
 
 func (m *mysqlUserRepository) GetByEmail(ctx context.Context, title string) (res models.User, err error) {
-	query := `SELECT * FROM user WHERE email = ?`
+	query := `SELECT id, name, email, password, email_verified_at FROM user WHERE email = ? AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, title)
 	if err != nil {
@@ -117,3 +188,88 @@ func (m *mysqlUserRepository) GetByEmail(ctx context.Context, title string) (res
 	}
 	return
 }
+
+func (m *mysqlUserRepository) UpdatePassword(ctx context.Context, id int64, hash string) (err error) {
+	query := `UPDATE user SET password=? WHERE ID = ? AND deleted_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, hash, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+		return
+	}
+
+	return
+}
+
+func (m *mysqlUserRepository) Update(ctx context.Context, u *models.User) (err error) {
+	query := `UPDATE user SET name=?, email=?, email_verified_at=? WHERE ID = ? AND deleted_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, u.Name, u.Email, u.EmailVerifiedAt, u.ID)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	// MySQL reports unchanged rows as not affected, tell them apart from missing ones
+	if affect == 0 {
+		_, err = m.GetByID(ctx, u.ID)
+	}
+	return
+}
+
+// Delete marks the user as deleted, the row itself stays
+func (m *mysqlUserRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := `UPDATE user SET deleted_at=NOW() WHERE ID = ? AND deleted_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect == 0 {
+		return utility.ErrNotFound
+	}
+	return
+}
+
+// MarkEmailVerified keeps the time the email was first verified
+func (m *mysqlUserRepository) MarkEmailVerified(ctx context.Context, id int64) (err error) {
+	query := `UPDATE user SET email_verified_at=? WHERE ID = ? AND email_verified_at IS NULL AND deleted_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, time.Now(), id)
+	return
+}
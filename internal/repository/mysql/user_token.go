@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kecci/goscription/internal/library/db"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+// UserTokenRepository represent the repository contract
+type UserTokenRepository interface {
+	Store(ctx context.Context, t *models.UserToken) (err error)
+	GetByHash(ctx context.Context, hash string) (res models.UserToken, err error)
+	Use(ctx context.Context, id int64) (err error)
+	UseByUser(ctx context.Context, userID int64, purpose string) (err error)
+}
+
+type mysqlUserTokenRepository struct {
+	Conn *sql.DB
+}
+
+// NewUserTokenRepository will create an object that represent the UserTokenRepository interface
+func NewUserTokenRepository(DB db.Database) UserTokenRepository {
+	if DB.Mysql == nil {
+		panic("Database Connections is nil")
+	}
+	return &mysqlUserTokenRepository{DB.Mysql}
+}
+
+func (m *mysqlUserTokenRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []models.UserToken, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		utility.Logger(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}()
+
+	result = make([]models.UserToken, 0)
+	for rows.Next() {
+		t := models.UserToken{}
+		err = rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.Purpose,
+			&t.TokenHash,
+			&t.ExpiresAt,
+			&t.UsedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlUserTokenRepository) Store(ctx context.Context, t *models.UserToken) (err error) {
+	query := `INSERT user_token SET user_id=?, purpose=?, token_hash=?, expires_at=?, created_at=?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	t.CreatedAt = time.Now()
+	res, err := stmt.ExecContext(ctx, t.UserID, t.Purpose, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	t.ID = lastID
+	return
+}
+
+func (m *mysqlUserTokenRepository) GetByHash(ctx context.Context, hash string) (res models.UserToken, err error) {
+	query := `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at
+  						FROM user_token WHERE token_hash = ?`
+
+	list, err := m.fetch(ctx, query, hash)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, utility.ErrNotFound
+	}
+	return
+}
+
+// Use marks an unused token as used, a token that is already used yields ErrNotFound
+// so that a token cannot be redeemed twice, even concurrently.
+func (m *mysqlUserTokenRepository) Use(ctx context.Context, id int64) (err error) {
+	query := `UPDATE user_token SET used_at=? WHERE id = ? AND used_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now(), id)
+	if err != nil {
+		return
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affect != 1 {
+		return utility.ErrNotFound
+	}
+	return
+}
+
+// UseByUser voids every unused token of a user issued for purpose
+func (m *mysqlUserTokenRepository) UseByUser(ctx context.Context, userID int64, purpose string) (err error) {
+	query := `UPDATE user_token SET used_at=? WHERE user_id = ? AND purpose = ? AND used_at IS NULL`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, time.Now(), userID, purpose)
+	return
+}
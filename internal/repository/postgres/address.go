@@ -1,43 +1,191 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/jackc/pgconn"
 	"github.com/kecci/goscription/internal/library/db"
 	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// uniqueViolation is the SQLSTATE of a unique index violation
+const uniqueViolation = "23505"
+
 type (
+	// AddressRepository represent the address's repository contract
 	AddressRepository interface {
-		Insert(address models.Address) (err error)
-		GetAddressAll() (addresses []models.Address, err error)
+		Fetch(ctx context.Context, userID int64, cursor string, num int64) (res []models.Address, nextCursor string, err error)
+		GetByID(ctx context.Context, userID, id int64) (res models.Address, err error)
+		Store(ctx context.Context, a *models.Address) (err error)
+		Update(ctx context.Context, a *models.Address) (err error)
+		Delete(ctx context.Context, userID, id int64) (err error)
 	}
 
+	// AddressRepositoryImpl keeps at most one primary address per user. Every write runs
+	// in a transaction holding the row locks of the user's addresses, and the partial
+	// unique index on "primary" settles the races that find no row to lock.
 	AddressRepositoryImpl struct {
 		DB *gorm.DB
 	}
 )
 
+// NewAddressRepository will create an object that represent the AddressRepository interface
 func NewAddressRepository(db db.Database) AddressRepository {
 	if db.Postgres == nil {
-		fmt.Println("db postgress nil")
+		panic("Postgres Connections is nil")
 	}
 	return &AddressRepositoryImpl{DB: db.Postgres}
 }
 
-func (r *AddressRepositoryImpl) Insert(address models.Address) (err error) {
-	tx := r.DB.Table("address").Create(&address).Scan(&address)
-	if err = tx.Error; err != nil {
-		fmt.Println(err.Error())
+func (r *AddressRepositoryImpl) Fetch(ctx context.Context, userID int64, cursor string, num int64) (res []models.Address, nextCursor string, err error) {
+	query := r.DB.WithContext(ctx).Table("address").Where("user_id = ?", userID).Order("id DESC").Limit(int(num))
+
+	if cursor != "" {
+		decodedCursor, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, "", utility.ErrBadParamInput
+		}
+		query = query.Where("id < ?", decodedCursor)
 	}
-	return err
+
+	res = []models.Address{}
+	if err = query.Find(&res).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor = cursor
+	if len(res) > 0 {
+		nextCursor = fmt.Sprintf("%d", res[len(res)-1].ID)
+	}
+	return
 }
 
-func (r *AddressRepositoryImpl) GetAddressAll() (addresses []models.Address, err error) {
-	tx := r.DB.Raw("SELECT * FROM address").Scan(&addresses)
-	if tx.Error != nil {
-		err = tx.Error
+func (r *AddressRepositoryImpl) GetByID(ctx context.Context, userID, id int64) (res models.Address, err error) {
+	err = r.DB.WithContext(ctx).Table("address").Where("id = ? AND user_id = ?", id, userID).Take(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Address{}, utility.ErrNotFound
 	}
 	return
 }
+
+// Store makes the first address of a user its primary one
+func (r *AddressRepositoryImpl) Store(ctx context.Context, a *models.Address) (err error) {
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := lockAddresses(tx, a.UserID)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			a.Primary = true
+		}
+		if a.Primary {
+			if err = unsetPrimary(tx, a.UserID, 0); err != nil {
+				return err
+			}
+		}
+		return tx.Table("address").Create(a).Error
+	})
+	return translate(err)
+}
+
+// Update keeps the user with a primary address, the primary flag cannot be dropped
+// from the primary address, another one has to be made primary instead
+func (r *AddressRepositoryImpl) Update(ctx context.Context, a *models.Address) (err error) {
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := lockAddresses(tx, a.UserID)
+		if err != nil {
+			return err
+		}
+
+		var current *models.Address
+		for i := range existing {
+			if existing[i].ID == a.ID {
+				current = &existing[i]
+			}
+		}
+		if current == nil {
+			return utility.ErrNotFound
+		}
+		if current.Primary && !a.Primary {
+			return utility.ErrBadParamInput.WithDetails(models.ErrorDetail{
+				Field:   "primary",
+				Message: "make another address primary instead",
+			})
+		}
+		if a.Primary && !current.Primary {
+			if err = unsetPrimary(tx, a.UserID, a.ID); err != nil {
+				return err
+			}
+		}
+
+		a.CreatedBy = current.CreatedBy
+		a.CreatedAt = current.CreatedAt
+		return tx.Table("address").
+			Where("id = ? AND user_id = ?", a.ID, a.UserID).
+			Select("address_title", "address_full", "district_name", "subdistrict_name", "zip_code", "primary", "updated_by", "updated_at").
+			Updates(a).Error
+	})
+	return translate(err)
+}
+
+// Delete hands the primary flag over to the oldest remaining address
+func (r *AddressRepositoryImpl) Delete(ctx context.Context, userID, id int64) (err error) {
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := lockAddresses(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		var (
+			deleted *models.Address
+			oldest  *models.Address
+		)
+		for i := range existing {
+			switch {
+			case existing[i].ID == id:
+				deleted = &existing[i]
+			case oldest == nil || existing[i].ID < oldest.ID:
+				oldest = &existing[i]
+			}
+		}
+		if deleted == nil {
+			return utility.ErrNotFound
+		}
+
+		if err = tx.Table("address").Where("id = ?", id).Delete(&models.Address{}).Error; err != nil {
+			return err
+		}
+		if deleted.Primary && oldest != nil {
+			return tx.Table("address").Where("id = ?", oldest.ID).Update("primary", true).Error
+		}
+		return nil
+	})
+	return translate(err)
+}
+
+// lockAddresses reads every address of a user FOR UPDATE
+func lockAddresses(tx *gorm.DB, userID int64) (res []models.Address, err error) {
+	err = tx.Table("address").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).Order("id").Find(&res).Error
+	return
+}
+
+// unsetPrimary clears the primary flag of every address of the user but the given one
+func unsetPrimary(tx *gorm.DB, userID, keepID int64) error {
+	return tx.Table("address").Where(`user_id = ? AND id <> ? AND "primary"`, userID, keepID).Update("primary", false).Error
+}
+
+// translate reports a concurrent primary switch as a conflict
+func translate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return utility.ErrConflict.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/models"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// Module for the background jobs
+var Module = fx.Invoke(Register)
+
+// Register runs the background jobs for as long as the application runs
+func Register(lc fx.Lifecycle, config models.Config, articleService service.ArticleService) {
+	publishInterval := time.Duration(config.Scheduler.PublishInterval) * time.Second
+	if publishInterval <= 0 {
+		publishInterval = time.Minute
+	}
+
+	purgeInterval := time.Duration(config.Scheduler.PurgeInterval) * time.Second
+	if purgeInterval <= 0 {
+		purgeInterval = time.Hour
+	}
+	trashRetention := time.Duration(config.Scheduler.TrashRetention) * time.Second
+	if trashRetention <= 0 {
+		trashRetention = 30 * 24 * time.Hour
+	}
+
+	s := New(Job{
+		Name:     "publish-articles",
+		Interval: publishInterval,
+		Run: func(ctx context.Context) error {
+			published, err := articleService.PublishDue(ctx)
+			if published > 0 {
+				logrus.Printf("Published %d scheduled articles.", published)
+			}
+			return err
+		},
+	}, Job{
+		Name:     "purge-trash",
+		Interval: purgeInterval,
+		Run: func(ctx context.Context) error {
+			purged, err := articleService.PurgeTrash(ctx, trashRetention)
+			if purged > 0 {
+				logrus.Printf("Purged %d trashed articles.", purged)
+			}
+			return err
+		},
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logrus.Print("Starting scheduler.")
+			s.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logrus.Print("Stopping scheduler.")
+			return s.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kecci/goscription/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	var runs int32
+	s := scheduler.New(scheduler.Job{
+		Name:     "count",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return errors.New("failing runs are retried")
+		},
+	})
+
+	s.Start()
+	time.Sleep(55 * time.Millisecond)
+	assert.NoError(t, s.Stop(context.Background()))
+
+	stopped := atomic.LoadInt32(&runs)
+	assert.True(t, stopped >= 3, "ran %d times", stopped)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+func TestSchedulerStopTimeout(t *testing.T) {
+	s := scheduler.New(scheduler.Job{
+		Name:     "stuck",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	})
+
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, s.Stop(ctx))
+}
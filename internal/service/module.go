@@ -8,5 +8,12 @@ import (
 var Module = fx.Provide(
 	NewArticleService,
 	NewUserService,
+	NewAccountService,
 	NewHealthService,
+	NewAuthService,
+	NewRoleService,
+	NewDomainService,
+	NewBreakerService,
+	NewAddressService,
+	NewTagService,
 )
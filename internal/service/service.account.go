@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kecci/goscription/internal/library/mailer"
+	"github.com/kecci/goscription/internal/library/password"
+	"github.com/kecci/goscription/internal/repository/mysql"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// AccountService represent the service of the mailed account flows, email
+	// verification and password reset
+	AccountService interface {
+		SendVerification(ctx context.Context, user models.User) (err error)
+		VerifyEmail(ctx context.Context, token string) (err error)
+		ResendVerification(ctx context.Context, email string) (err error)
+		ForgotPassword(ctx context.Context, email string) (err error)
+		ResetPassword(ctx context.Context, token, plain string) (err error)
+	}
+
+	// AccountServiceImpl represent the service of the mailed account flows
+	AccountServiceImpl struct {
+		userRepo       mysql.UserRepository
+		userTokenRepo  mysql.UserTokenRepository
+		refreshRepo    mysql.RefreshTokenRepository
+		mailer         mailer.Mailer
+		hasher         password.Hasher
+		baseURL        string
+		verifyTTL      time.Duration
+		resetTTL       time.Duration
+		contextTimeout time.Duration
+	}
+)
+
+// NewAccountService will create new an accountService object representation of service.AccountService interface
+func NewAccountService(u mysql.UserRepository, ut mysql.UserTokenRepository, r mysql.RefreshTokenRepository, m mailer.Mailer, h password.Hasher, config models.Config, timeout time.Duration) AccountService {
+	if u == nil {
+		panic("User repository is nil")
+	}
+	if ut == nil {
+		panic("User token repository is nil")
+	}
+	if m == nil {
+		panic("Mailer is nil")
+	}
+
+	verifyTTL := time.Duration(config.Mail.VerifyTokenTTL) * time.Second
+	if verifyTTL == 0 {
+		verifyTTL = 24 * time.Hour
+	}
+	resetTTL := time.Duration(config.Mail.ResetTokenTTL) * time.Second
+	if resetTTL == 0 {
+		resetTTL = time.Hour
+	}
+
+	return &AccountServiceImpl{
+		userRepo:       u,
+		userTokenRepo:  ut,
+		refreshRepo:    r,
+		mailer:         m,
+		hasher:         h,
+		baseURL:        strings.TrimRight(config.Mail.BaseURL, "/"),
+		verifyTTL:      verifyTTL,
+		resetTTL:       resetTTL,
+		contextTimeout: timeout,
+	}
+}
+
+// SendVerification mails a fresh verification link to the user, voiding the previous ones
+func (a *AccountServiceImpl) SendVerification(c context.Context, user models.User) (err error) {
+	c, span := utility.StartSpan(c, "AccountService.SendVerification")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	token, err := a.issue(ctx, user.ID, models.TokenPurposeVerifyEmail, a.verifyTTL)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by following this link:\n%s\n\nThe link expires in %s.\n",
+			user.Name, a.link("/verify-email", token), a.verifyTTL),
+	})
+}
+
+// VerifyEmail redeems a verification token
+func (a *AccountServiceImpl) VerifyEmail(c context.Context, token string) (err error) {
+	c, span := utility.StartSpan(c, "AccountService.VerifyEmail")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.redeem(ctx, token, models.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+	return a.userRepo.MarkEmailVerified(ctx, stored.UserID)
+}
+
+// ResendVerification mails a new verification link. Unknown and already verified
+// emails are ignored, so that the endpoint tells nothing about who is registered.
+func (a *AccountServiceImpl) ResendVerification(c context.Context, email string) (err error) {
+	c, span := utility.StartSpan(c, "AccountService.ResendVerification")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, utility.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return a.SendVerification(ctx, user)
+}
+
+// ForgotPassword mails a password reset link, unknown emails are ignored
+func (a *AccountServiceImpl) ForgotPassword(c context.Context, email string) (err error) {
+	c, span := utility.StartSpan(c, "AccountService.ForgotPassword")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, utility.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := a.issue(ctx, user.ID, models.TokenPurposeResetPassword, a.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nA password reset was requested for your account. Choose a new password by following this link:\n%s\n\nThe link expires in %s. If you did not ask for it, ignore this mail.\n",
+			user.Name, a.link("/reset-password", token), a.resetTTL),
+	})
+}
+
+// ResetPassword redeems a reset token. Following the mailed link proves the ownership
+// of the email as well, and every session of the user is signed out.
+func (a *AccountServiceImpl) ResetPassword(c context.Context, token, plain string) (err error) {
+	c, span := utility.StartSpan(c, "AccountService.ResetPassword")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.redeem(ctx, token, models.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	hash, err := a.hasher.Hash(plain)
+	if err != nil {
+		return err
+	}
+	if err = a.userRepo.UpdatePassword(ctx, stored.UserID, hash); err != nil {
+		return err
+	}
+	if err = a.userRepo.MarkEmailVerified(ctx, stored.UserID); err != nil {
+		utility.Logger(ctx).Error(err)
+	}
+	return a.refreshRepo.RevokeByUser(ctx, stored.UserID)
+}
+
+// issue stores a new token for purpose and returns it, the unused tokens issued
+// before for the same purpose stop working
+func (a *AccountServiceImpl) issue(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, error) {
+	if err := a.userTokenRepo.UseByUser(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	err = a.userTokenRepo.Store(ctx, &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// redeem marks the token as used, provided it was issued for purpose and is still valid
+func (a *AccountServiceImpl) redeem(ctx context.Context, token, purpose string) (models.UserToken, error) {
+	stored, err := a.userTokenRepo.GetByHash(ctx, hashToken(token))
+	if errors.Is(err, utility.ErrNotFound) {
+		return models.UserToken{}, utility.ErrInvalidToken
+	}
+	if err != nil {
+		return models.UserToken{}, err
+	}
+	if stored.Purpose != purpose || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return models.UserToken{}, utility.ErrInvalidToken
+	}
+
+	err = a.userTokenRepo.Use(ctx, stored.ID)
+	if errors.Is(err, utility.ErrNotFound) {
+		// lost the race against a concurrent use of the same token
+		return models.UserToken{}, utility.ErrInvalidToken
+	}
+	if err != nil {
+		return models.UserToken{}, err
+	}
+	return stored, nil
+}
+
+func (a *AccountServiceImpl) link(path, token string) string {
+	return a.baseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kecci/goscription/internal/library/mailer"
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+var mailedToken = regexp.MustCompile(`\?token=(\S+)`)
+
+func newAccountService(u *mocks.UserRepository, ut *mocks.UserTokenRepository, r *mocks.RefreshTokenRepository, m *mocks.Mailer) service.AccountService {
+	config := models.Config{Mail: models.Mail{BaseURL: "http://localhost:3000/"}}
+	return service.NewAccountService(u, ut, r, m, newHasher(4), config, time.Second*2)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestSendVerification(t *testing.T) {
+	mockUserTokenRepo := new(mocks.UserTokenRepository)
+	mockMailer := new(mocks.Mailer)
+	mockUser := models.User{ID: 7, Name: "Hello", Email: "hello@example.com"}
+
+	var stored *models.UserToken
+	mockUserTokenRepo.On("UseByUser", mock.Anything, mockUser.ID, models.TokenPurposeVerifyEmail).Return(nil).Once()
+	mockUserTokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(ut *models.UserToken) bool {
+		stored = ut
+		return ut.UserID == mockUser.ID && ut.Purpose == models.TokenPurposeVerifyEmail
+	})).Return(nil).Once()
+	mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(msg mailer.Message) bool {
+		match := mailedToken.FindStringSubmatch(msg.Body)
+		if msg.To != mockUser.Email || match == nil || stored == nil {
+			return false
+		}
+		token, _ := url.QueryUnescape(match[1])
+		// the link carries the token, the database only its hash
+		return stored.TokenHash == sha256Hex(token) && regexp.MustCompile(`http://localhost:3000/verify-email\?`).MatchString(msg.Body)
+	})).Return(nil).Once()
+
+	a := newAccountService(new(mocks.UserRepository), mockUserTokenRepo, new(mocks.RefreshTokenRepository), mockMailer)
+
+	err := a.SendVerification(context.TODO(), mockUser)
+
+	assert.NoError(t, err)
+	assert.True(t, stored.ExpiresAt.After(time.Now().Add(23*time.Hour)))
+	mockUserTokenRepo.AssertExpectations(t)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	valid := models.UserToken{ID: 3, UserID: 7, Purpose: models.TokenPurposeVerifyEmail, TokenHash: sha256Hex("t0ken"), ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserTokenRepo := new(mocks.UserTokenRepository)
+		mockUserTokenRepo.On("GetByHash", mock.Anything, valid.TokenHash).Return(valid, nil).Once()
+		mockUserTokenRepo.On("Use", mock.Anything, valid.ID).Return(nil).Once()
+		mockUserRepo.On("MarkEmailVerified", mock.Anything, valid.UserID).Return(nil).Once()
+
+		a := newAccountService(mockUserRepo, mockUserTokenRepo, new(mocks.RefreshTokenRepository), new(mocks.Mailer))
+
+		err := a.VerifyEmail(context.TODO(), "t0ken")
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockUserTokenRepo.AssertExpectations(t)
+	})
+
+	expired := valid
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	usedAt := time.Now()
+	used := valid
+	used.UsedAt = &usedAt
+	reset := valid
+	reset.Purpose = models.TokenPurposeResetPassword
+
+	for name, token := range map[string]models.UserToken{"expired": expired, "used": used, "other-purpose": reset} {
+		t.Run(name, func(t *testing.T) {
+			mockUserTokenRepo := new(mocks.UserTokenRepository)
+			mockUserTokenRepo.On("GetByHash", mock.Anything, valid.TokenHash).Return(token, nil).Once()
+
+			a := newAccountService(new(mocks.UserRepository), mockUserTokenRepo, new(mocks.RefreshTokenRepository), new(mocks.Mailer))
+
+			err := a.VerifyEmail(context.TODO(), "t0ken")
+
+			assert.Equal(t, utility.ErrInvalidToken, err)
+			mockUserTokenRepo.AssertNotCalled(t, "Use", mock.Anything, mock.Anything)
+		})
+	}
+	t.Run("used-concurrently", func(t *testing.T) {
+		mockUserTokenRepo := new(mocks.UserTokenRepository)
+		mockUserTokenRepo.On("GetByHash", mock.Anything, valid.TokenHash).Return(valid, nil).Once()
+		mockUserTokenRepo.On("Use", mock.Anything, valid.ID).Return(utility.ErrNotFound).Once()
+
+		a := newAccountService(new(mocks.UserRepository), mockUserTokenRepo, new(mocks.RefreshTokenRepository), new(mocks.Mailer))
+
+		err := a.VerifyEmail(context.TODO(), "t0ken")
+
+		assert.Equal(t, utility.ErrInvalidToken, err)
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	t.Run("unknown-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockMailer := new(mocks.Mailer)
+		mockUserRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(models.User{}, utility.ErrNotFound).Once()
+
+		a := newAccountService(mockUserRepo, new(mocks.UserTokenRepository), new(mocks.RefreshTokenRepository), mockMailer)
+
+		err := a.ForgotPassword(context.TODO(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserTokenRepo := new(mocks.UserTokenRepository)
+		mockMailer := new(mocks.Mailer)
+		mockUser := models.User{ID: 7, Email: "hello@example.com"}
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil).Once()
+		mockUserTokenRepo.On("UseByUser", mock.Anything, mockUser.ID, models.TokenPurposeResetPassword).Return(nil).Once()
+		mockUserTokenRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.UserToken")).Return(nil).Once()
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(msg mailer.Message) bool {
+			return msg.To == mockUser.Email && regexp.MustCompile(`/reset-password\?token=`).MatchString(msg.Body)
+		})).Return(nil).Once()
+
+		a := newAccountService(mockUserRepo, mockUserTokenRepo, new(mocks.RefreshTokenRepository), mockMailer)
+
+		err := a.ForgotPassword(context.TODO(), mockUser.Email)
+
+		assert.NoError(t, err)
+		mockUserTokenRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserTokenRepo := new(mocks.UserTokenRepository)
+	mockRefreshRepo := new(mocks.RefreshTokenRepository)
+	token := models.UserToken{ID: 3, UserID: 7, Purpose: models.TokenPurposeResetPassword, TokenHash: sha256Hex("t0ken"), ExpiresAt: time.Now().Add(time.Hour)}
+	hasher := newHasher(4)
+
+	mockUserTokenRepo.On("GetByHash", mock.Anything, token.TokenHash).Return(token, nil).Once()
+	mockUserTokenRepo.On("Use", mock.Anything, token.ID).Return(nil).Once()
+	mockUserRepo.On("UpdatePassword", mock.Anything, token.UserID, mock.MatchedBy(func(hash string) bool {
+		ok, _ := hasher.Verify("n3w-s3cret", hash)
+		return ok
+	})).Return(nil).Once()
+	mockUserRepo.On("MarkEmailVerified", mock.Anything, token.UserID).Return(nil).Once()
+	mockRefreshRepo.On("RevokeByUser", mock.Anything, token.UserID).Return(nil).Once()
+
+	a := newAccountService(mockUserRepo, mockUserTokenRepo, mockRefreshRepo, new(mocks.Mailer))
+
+	err := a.ResetPassword(context.TODO(), "t0ken", "n3w-s3cret")
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockUserTokenRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
@@ -1,31 +1,181 @@
 package service
 
 import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/kecci/goscription/internal/repository/mysql"
 	"github.com/kecci/goscription/internal/repository/postgres"
 	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
 )
 
 type (
+	// AddressService represent the service of the user's addresses
 	AddressService interface {
-		Insert(address models.Address) error
-		GetAddressAll() ([]models.Address, error)
+		Fetch(ctx context.Context, userID int64, cursor string, num int64) (res []models.Address, nextCursor string, err error)
+		GetByID(ctx context.Context, userID, id int64) (res models.Address, err error)
+		Store(context.Context, AddressParam) (res models.Address, err error)
+		Update(context.Context, AddressParam) (res models.Address, err error)
+		Delete(ctx context.Context, userID, id int64) (err error)
 	}
 
+	// AddressServiceImpl represent the service of the user's addresses
 	AddressServiceImpl struct {
-		addressRepo postgres.AddressRepository
+		addressRepo    postgres.AddressRepository
+		userRepo       mysql.UserRepository
+		contextTimeout time.Duration
 	}
 )
 
-func NewAddressService(addressRepo postgres.AddressRepository) AddressService {
+// AddressParam ...
+type AddressParam struct {
+	ID              int64
+	UserID          int64
+	AddressTitle    string
+	AddressFull     string
+	DistrictName    string
+	SubdistrictName string
+	ZipCode         string
+	Primary         bool
+}
+
+// NewAddressService will create new an addressService object representation of service.AddressService interface
+func NewAddressService(a postgres.AddressRepository, u mysql.UserRepository, timeout time.Duration) AddressService {
+	if a == nil {
+		panic("Address repository is nil")
+	}
+	if u == nil {
+		panic("User repository is nil")
+	}
 	return &AddressServiceImpl{
-		addressRepo: addressRepo,
+		addressRepo:    a,
+		userRepo:       u,
+		contextTimeout: timeout,
+	}
+}
+
+// Fetch ...
+func (a *AddressServiceImpl) Fetch(c context.Context, userID int64, cursor string, num int64) (res []models.Address, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "AddressService.Fetch")
+	defer utility.EndSpan(span, &err)
+
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.authorize(ctx, userID); err != nil {
+		return nil, "", err
 	}
+	return a.addressRepo.Fetch(ctx, userID, cursor, num)
 }
 
-func (a *AddressServiceImpl) Insert(address models.Address) error {
-	return a.addressRepo.Insert(address)
+// GetByID ...
+func (a *AddressServiceImpl) GetByID(c context.Context, userID, id int64) (res models.Address, err error) {
+	c, span := utility.StartSpan(c, "AddressService.GetByID")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.authorize(ctx, userID); err != nil {
+		return models.Address{}, err
+	}
+	return a.addressRepo.GetByID(ctx, userID, id)
 }
 
-func (a *AddressServiceImpl) GetAddressAll() ([]models.Address, error) {
-	return a.addressRepo.GetAddressAll()
+// Store records the caller as the author of the address
+func (a *AddressServiceImpl) Store(c context.Context, p AddressParam) (res models.Address, err error) {
+	c, span := utility.StartSpan(c, "AddressService.Store")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	caller, err := a.authorize(ctx, p.UserID)
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	now := time.Now()
+	m := models.Address{
+		UserID:          p.UserID,
+		AddressTitle:    p.AddressTitle,
+		AddressFull:     p.AddressFull,
+		DistrictName:    p.DistrictName,
+		SubdistrictName: p.SubdistrictName,
+		ZipCode:         p.ZipCode,
+		Primary:         p.Primary,
+		CreatedBy:       caller,
+		CreatedAt:       now,
+		UpdatedBy:       caller,
+		UpdatedAt:       now,
+	}
+	if err = a.addressRepo.Store(ctx, &m); err != nil {
+		return models.Address{}, err
+	}
+	return m, nil
+}
+
+// Update records the caller as the last editor of the address
+func (a *AddressServiceImpl) Update(c context.Context, p AddressParam) (res models.Address, err error) {
+	c, span := utility.StartSpan(c, "AddressService.Update")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	caller, err := a.authorize(ctx, p.UserID)
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	m := models.Address{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		AddressTitle:    p.AddressTitle,
+		AddressFull:     p.AddressFull,
+		DistrictName:    p.DistrictName,
+		SubdistrictName: p.SubdistrictName,
+		ZipCode:         p.ZipCode,
+		Primary:         p.Primary,
+		UpdatedBy:       caller,
+		UpdatedAt:       time.Now(),
+	}
+	if err = a.addressRepo.Update(ctx, &m); err != nil {
+		return models.Address{}, err
+	}
+	return a.addressRepo.GetByID(ctx, p.UserID, p.ID)
+}
+
+// Delete ...
+func (a *AddressServiceImpl) Delete(c context.Context, userID, id int64) (err error) {
+	c, span := utility.StartSpan(c, "AddressService.Delete")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.authorize(ctx, userID); err != nil {
+		return err
+	}
+	return a.addressRepo.Delete(ctx, userID, id)
+}
+
+// authorize lets the owner of the addresses, or a user manager, through. It returns the
+// caller id in the form the address audit columns keep it.
+func (a *AddressServiceImpl) authorize(ctx context.Context, userID int64) (string, error) {
+	caller, err := authorizeUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = a.userRepo.GetByID(ctx, userID); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(caller.ID, 10), nil
 }
@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+func TestStoreAddress(t *testing.T) {
+	mockAddressParam := service.AddressParam{
+		UserID:       7,
+		AddressTitle: "Home",
+		AddressFull:  "Jl. Sudirman No. 1",
+		ZipCode:      "12190",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAddressRepo := new(mocks.AddressRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+		mockAddressRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *models.Address) bool {
+			return a.UserID == 7 && a.CreatedBy == "7" && a.UpdatedBy == "7"
+		})).Return(nil).Once()
+
+		u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+		res, err := u.Store(ctx, mockAddressParam)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockAddressParam.AddressTitle, res.AddressTitle)
+		mockAddressRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("user-manager", func(t *testing.T) {
+		mockAddressRepo := new(mocks.AddressRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+		mockAddressRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *models.Address) bool {
+			return a.UserID == 7 && a.CreatedBy == "1"
+		})).Return(nil).Once()
+
+		u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionUserManage}})
+
+		_, err := u.Store(ctx, mockAddressParam)
+
+		assert.NoError(t, err)
+		mockAddressRepo.AssertExpectations(t)
+	})
+	t.Run("other-user", func(t *testing.T) {
+		mockAddressRepo := new(mocks.AddressRepository)
+		mockUserRepo := new(mocks.UserRepository)
+
+		u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8})
+
+		_, err := u.Store(ctx, mockAddressParam)
+
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockAddressRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("unknown-user", func(t *testing.T) {
+		mockAddressRepo := new(mocks.AddressRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{}, utility.ErrNotFound).Once()
+
+		u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionUserManage}})
+
+		_, err := u.Store(ctx, mockAddressParam)
+
+		assert.Equal(t, utility.ErrNotFound, err)
+		mockAddressRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("anonymous", func(t *testing.T) {
+		u := service.NewAddressService(new(mocks.AddressRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Store(context.TODO(), mockAddressParam)
+
+		assert.Equal(t, utility.ErrUnauthorized, err)
+	})
+}
+
+func TestUpdateAddress(t *testing.T) {
+	mockAddressRepo := new(mocks.AddressRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockAddress := models.Address{ID: 3, UserID: 7, AddressTitle: "Office", Primary: true, CreatedBy: "7", UpdatedBy: "7"}
+
+	mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+	mockAddressRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *models.Address) bool {
+		return a.ID == 3 && a.Primary && a.UpdatedBy == "7"
+	})).Return(nil).Once()
+	mockAddressRepo.On("GetByID", mock.Anything, int64(7), int64(3)).Return(mockAddress, nil).Once()
+
+	u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	res, err := u.Update(ctx, service.AddressParam{ID: 3, UserID: 7, AddressTitle: "Office", AddressFull: "Jl. Thamrin", Primary: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockAddress, res)
+	mockAddressRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestFetchAddress(t *testing.T) {
+	mockAddressRepo := new(mocks.AddressRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockList := []models.Address{{ID: 5, UserID: 7}, {ID: 4, UserID: 7}}
+
+	mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+	mockAddressRepo.On("Fetch", mock.Anything, int64(7), "", int64(10)).Return(mockList, "4", nil).Once()
+
+	u := service.NewAddressService(mockAddressRepo, mockUserRepo, time.Second*2)
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	list, nextCursor, err := u.Fetch(ctx, 7, "", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4", nextCursor)
+	assert.Len(t, list, 2)
+	mockAddressRepo.AssertExpectations(t)
+}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"time"
 
+	"github.com/kecci/goscription/internal/metrics"
 	"github.com/kecci/goscription/internal/repository/mysql"
 	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
@@ -12,44 +16,79 @@ import (
 type (
 	// ArticleService represent the service of the article
 	ArticleService interface {
-		Fetch(ctx context.Context, cursor string, num int64) (res []models.Article, csr string, err error)
+		Fetch(ctx context.Context, filter models.ArticleFilter, cursor string, num int64) (res []models.Article, csr string, err error)
+		Search(ctx context.Context, q string, cursor string, num int64) (res []models.ArticleSearchResult, csr string, err error)
 		GetByID(ctx context.Context, id int64) (res models.Article, err error)
-		Update(context.Context, ArticleParam) (err error)
+		Update(context.Context, ArticleParam) (res models.Article, err error)
 		GetByTitle(ctx context.Context, title string) (res models.Article, err error)
-		Store(context.Context, ArticleParam) (err error)
+		Store(context.Context, ArticleParam) (res models.Article, err error)
 		Delete(ctx context.Context, id int64) (err error)
+		Revisions(ctx context.Context, id int64, cursor string, num int64) (res []models.ArticleRevision, csr string, err error)
+		Diff(ctx context.Context, id, from, to int64) (res models.ArticleDiff, err error)
+		Restore(ctx context.Context, id, rev, version int64) (res models.Article, err error)
+		PublishDue(ctx context.Context) (published int64, err error)
+		Trash(ctx context.Context, cursor string, num int64) (res []models.Article, csr string, err error)
+		RestoreTrashed(ctx context.Context, id int64) (res models.Article, err error)
+		PurgeTrash(ctx context.Context, retention time.Duration) (purged int64, err error)
 	}
 
 	// ArticleServiceImpl represent the service of the article
 	ArticleServiceImpl struct {
 		articleRepo    mysql.ArticleRepository
+		revisionRepo   mysql.ArticleRevisionRepository
+		userRepo       mysql.UserRepository
 		contextTimeout time.Duration
 	}
 )
 
+const (
+	// maxSearchLength bounds the length of a search query
+	maxSearchLength = 200
+	// snippetLength is the length, in characters, of the content excerpt of a search result
+	snippetLength = 160
+)
+
 // ArticleParam ...
 type ArticleParam struct {
 	ID      int64  `json:"id"`
-	Title   string `json:"title" validate:"required"`
+	Title   string `json:"title" validate:"required,max=45"`
 	Content string `json:"content" validate:"required"`
+	Version int64  `json:"version"`
+	// Tags replaces the tags of the article, nil keeps them as they are
+	Tags []string `json:"tags"`
+	// Status defaults to draft for a new article and to the current status on update
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 // NewArticleService will create new an articleService object representation of service.ArticleService interface
-func NewArticleService(a mysql.ArticleRepository, timeout time.Duration) ArticleService {
+func NewArticleService(a mysql.ArticleRepository, r mysql.ArticleRevisionRepository, u mysql.UserRepository, timeout time.Duration) ArticleService {
 	if a == nil {
 		panic("Article repository is nil")
 	}
+	if r == nil {
+		panic("Article revision repository is nil")
+	}
+	if u == nil {
+		panic("User repository is nil")
+	}
 	if timeout == 0 {
 		panic("Timeout is empty")
 	}
 	return &ArticleServiceImpl{
 		articleRepo:    a,
+		revisionRepo:   r,
+		userRepo:       u,
 		contextTimeout: timeout,
 	}
 }
 
-// Fetch ...
-func (a *ArticleServiceImpl) Fetch(c context.Context, cursor string, num int64) (res []models.Article, nextCursor string, err error) {
+// Fetch lists the published articles. The authors see every status of their own
+// articles and the editors can ask for any status, an unknown author is ErrNotFound.
+func (a *ArticleServiceImpl) Fetch(c context.Context, filter models.ArticleFilter, cursor string, num int64) (res []models.Article, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Fetch")
+	defer utility.EndSpan(span, &err)
+
 	if num == 0 {
 		num = 10
 	}
@@ -57,7 +96,27 @@ func (a *ArticleServiceImpl) Fetch(c context.Context, cursor string, num int64)
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+	if filter.AuthorID != 0 {
+		if _, err = a.userRepo.GetByID(ctx, filter.AuthorID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	caller, _ := utility.AuthUserFromContext(ctx)
+	own := filter.AuthorID != 0 && filter.AuthorID == caller.ID
+	switch {
+	case own || caller.HasPermission(models.PermissionArticleManage):
+		if filter.Status == "" && !own {
+			filter.Status = models.ArticleStatusPublished
+		}
+	case filter.Status == "" || filter.Status == models.ArticleStatusPublished:
+		filter.Status = models.ArticleStatusPublished
+	default:
+		return nil, "", utility.ErrForbidden
+	}
+
+	filter.Tags = normalizeTags(filter.Tags)
+	res, nextCursor, err = a.articleRepo.Fetch(ctx, filter, cursor, num)
 	if err != nil {
 		return nil, "", err
 	}
@@ -65,66 +124,412 @@ func (a *ArticleServiceImpl) Fetch(c context.Context, cursor string, num int64)
 	return
 }
 
-// GetByID ...
-func (a *ArticleServiceImpl) GetByID(c context.Context, id int64) (res models.Article, err error) {
+// Search ranks the articles matching q and highlights the matched terms in each of them
+func (a *ArticleServiceImpl) Search(c context.Context, q string, cursor string, num int64) (res []models.ArticleSearchResult, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Search")
+	defer utility.EndSpan(span, &err)
+
+	terms := utility.SearchTerms(q)
+	if len(terms) == 0 || len(q) > maxSearchLength {
+		return nil, "", utility.ErrBadParamInput
+	}
+	if num == 0 {
+		num = 10
+	}
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, err = a.articleRepo.GetByID(ctx, id)
+	res, nextCursor, err = a.articleRepo.Search(ctx, strings.TrimSpace(q), cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range res {
+		res[i].Highlight = models.ArticleHighlight{
+			Title:   utility.Highlight(res[i].Title, terms),
+			Snippet: utility.Snippet(res[i].Content, terms, snippetLength),
+		}
+	}
 	return
 }
 
-// Update ...
-func (a *ArticleServiceImpl) Update(c context.Context, ap ArticleParam) (err error) {
+// GetByID hides the unpublished articles from anyone but their author and the editors
+func (a *ArticleServiceImpl) GetByID(c context.Context, id int64) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.GetByID")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.visible(ctx, id)
+}
+
+// Update applies ap only when ap.Version matches the stored article, returning the fresh copy.
+// Only the author of the article or an editor may change it.
+func (a *ArticleServiceImpl) Update(c context.Context, ap ArticleParam) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Update")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
+	existedArticle, err := a.articleRepo.GetByID(ctx, ap.ID)
+	if err != nil {
+		return models.Article{}, err
+	}
+	caller, err := authorizeArticle(ctx, existedArticle)
+	if err != nil {
+		return models.Article{}, err
+	}
+
 	ar := models.Article{
 		ID:        ap.ID,
 		Title:     ap.Title,
 		Content:   ap.Content,
+		Version:   ap.Version,
+		Tags:      normalizeTags(ap.Tags),
 		UpdatedAt: time.Now(),
 	}
+	if err = applyStatus(&ar, existedArticle, ap.Status, ap.PublishAt); err != nil {
+		return models.Article{}, err
+	}
+
+	err = a.articleRepo.Update(ctx, &ar, caller.ID)
+	if err != nil {
+		return models.Article{}, err
+	}
 
-	return a.articleRepo.Update(ctx, &ar)
+	return a.articleRepo.GetByID(ctx, ar.ID)
 }
 
 // GetByTitle ...
 func (a *ArticleServiceImpl) GetByTitle(c context.Context, title string) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.GetByTitle")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	res, err = a.articleRepo.GetByTitle(ctx, title)
 	return
 }
 
-// Store ...
-func (a *ArticleServiceImpl) Store(c context.Context, p ArticleParam) (err error) {
+// Store records the caller as the author of the article, and returns it as stored
+func (a *ArticleServiceImpl) Store(c context.Context, p ArticleParam) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Store")
+	defer utility.EndSpan(span, &err)
+
+	caller, ok := utility.AuthUserFromContext(c)
+	if !ok {
+		return models.Article{}, utility.ErrUnauthorized
+	}
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedArticle, _ := a.GetByTitle(ctx, p.Title)
-	if existedArticle != (models.Article{}) {
-		return utility.ErrConflict
+	if !reflect.DeepEqual(existedArticle, models.Article{}) {
+		return models.Article{}, utility.ErrConflict
 	}
 
 	m := models.Article{
 		Title:   p.Title,
 		Content: p.Content,
+		Tags:    normalizeTags(p.Tags),
+		Author:  &models.ArticleAuthor{ID: caller.ID},
+	}
+	if err = applyStatus(&m, models.Article{Status: models.ArticleStatusDraft}, p.Status, p.PublishAt); err != nil {
+		return models.Article{}, err
 	}
 
-	err = a.articleRepo.Store(ctx, &m)
-	return
+	if err = a.articleRepo.Store(ctx, &m); err != nil {
+		return models.Article{}, err
+	}
+	metrics.ArticlesCreated.Inc()
+
+	return a.articleRepo.GetByID(ctx, m.ID)
 }
 
-// Delete ...
+// Delete moves the article to the trash, it is allowed to the author of the article or an editor
 func (a *ArticleServiceImpl) Delete(c context.Context, id int64) (err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Delete")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
-	if existedArticle == (models.Article{}) {
+	if reflect.DeepEqual(existedArticle, models.Article{}) {
 		return utility.ErrNotFound
 	}
+	if _, err = authorizeArticle(ctx, existedArticle); err != nil {
+		return err
+	}
 	return a.articleRepo.Delete(ctx, id)
 }
+
+// Revisions lists the past states of an article, newest first
+func (a *ArticleServiceImpl) Revisions(c context.Context, id int64, cursor string, num int64) (res []models.ArticleRevision, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Revisions")
+	defer utility.EndSpan(span, &err)
+
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.visible(ctx, id); err != nil {
+		return nil, "", err
+	}
+	return a.revisionRepo.Fetch(ctx, id, cursor, num)
+}
+
+// Diff compares two versions of an article line by line, either of them may be the current one
+func (a *ArticleServiceImpl) Diff(c context.Context, id, from, to int64) (res models.ArticleDiff, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Diff")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	current, err := a.visible(ctx, id)
+	if err != nil {
+		return models.ArticleDiff{}, err
+	}
+	older, err := a.version(ctx, current, from)
+	if err != nil {
+		return models.ArticleDiff{}, err
+	}
+	newer, err := a.version(ctx, current, to)
+	if err != nil {
+		return models.ArticleDiff{}, err
+	}
+
+	return models.ArticleDiff{
+		ArticleID: id,
+		From:      from,
+		To:        to,
+		Title:     utility.DiffLines(older.Title, newer.Title),
+		Content:   utility.DiffLines(older.Content, newer.Content),
+	}, nil
+}
+
+// Restore brings back the title and the content of the past version rev. It is an update like
+// any other, version is the one the caller expects to replace and the state it replaces becomes a revision in turn.
+func (a *ArticleServiceImpl) Restore(c context.Context, id, rev, version int64) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Restore")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	current, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return models.Article{}, err
+	}
+	caller, err := authorizeArticle(ctx, current)
+	if err != nil {
+		return models.Article{}, err
+	}
+	revision, err := a.revisionRepo.GetByVersion(ctx, id, rev)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	ar := models.Article{
+		ID:        id,
+		Title:     revision.Title,
+		Content:   revision.Content,
+		Version:   version,
+		Status:    current.Status,
+		PublishAt: current.PublishAt,
+	}
+	if err = a.articleRepo.Update(ctx, &ar, caller.ID); err != nil {
+		return models.Article{}, err
+	}
+
+	return a.articleRepo.GetByID(ctx, id)
+}
+
+// Trash lists the trashed articles of the caller, or every trashed article for an editor
+func (a *ArticleServiceImpl) Trash(c context.Context, cursor string, num int64) (res []models.Article, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.Trash")
+	defer utility.EndSpan(span, &err)
+
+	caller, ok := utility.AuthUserFromContext(c)
+	if !ok {
+		return nil, "", utility.ErrUnauthorized
+	}
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	filter := models.ArticleFilter{Trashed: true}
+	if !caller.HasPermission(models.PermissionArticleManage) {
+		filter.AuthorID = caller.ID
+	}
+	return a.articleRepo.Fetch(ctx, filter, cursor, num)
+}
+
+// RestoreTrashed takes an article back out of the trash, unless another article took its title meanwhile
+func (a *ArticleServiceImpl) RestoreTrashed(c context.Context, id int64) (res models.Article, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.RestoreTrashed")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	trashed, err := a.articleRepo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return models.Article{}, err
+	}
+	if _, err = authorizeArticle(ctx, trashed); err != nil {
+		return models.Article{}, err
+	}
+	_, err = a.articleRepo.GetByTitle(ctx, trashed.Title)
+	if err == nil {
+		return models.Article{}, utility.ErrConflict
+	}
+	if !errors.Is(err, utility.ErrNotFound) {
+		return models.Article{}, err
+	}
+
+	if err = a.articleRepo.RestoreTrashed(ctx, id); err != nil {
+		return models.Article{}, err
+	}
+	return a.articleRepo.GetByID(ctx, id)
+}
+
+// PurgeTrash permanently removes the articles trashed for longer than retention
+func (a *ArticleServiceImpl) PurgeTrash(c context.Context, retention time.Duration) (purged int64, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.PurgeTrash")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.articleRepo.Purge(ctx, time.Now().Add(-retention))
+}
+
+// PublishDue publishes the scheduled articles whose time has come
+func (a *ArticleServiceImpl) PublishDue(c context.Context) (published int64, err error) {
+	c, span := utility.StartSpan(c, "ArticleService.PublishDue")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	published, err = a.articleRepo.PublishDue(ctx, time.Now())
+	metrics.ArticlesPublished.Add(float64(published))
+	return
+}
+
+// visible loads an article, answering ErrNotFound when it is not published and the caller
+// is neither its author nor an editor
+func (a *ArticleServiceImpl) visible(ctx context.Context, id int64) (models.Article, error) {
+	res, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return models.Article{}, err
+	}
+	if res.Status == models.ArticleStatusPublished {
+		return res, nil
+	}
+	if _, err = authorizeArticle(ctx, res); err != nil {
+		return models.Article{}, utility.ErrNotFound
+	}
+	return res, nil
+}
+
+// applyStatus moves ar to status, empty keeping the status of current. Scheduling needs
+// a publish_at in the future, publishing stamps it unless current was published already.
+func applyStatus(ar *models.Article, current models.Article, status string, publishAt *time.Time) error {
+	if status == "" {
+		status = current.Status
+		if publishAt == nil {
+			publishAt = current.PublishAt
+		}
+	}
+
+	ar.Status = status
+	switch status {
+	case models.ArticleStatusScheduled:
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return utility.ErrBadParamInput.WithDetails(models.ErrorDetail{
+				Field:   "publish_at",
+				Rule:    "future",
+				Message: "must be in the future to schedule the article",
+			})
+		}
+		ar.PublishAt = publishAt
+	case models.ArticleStatusPublished:
+		ar.PublishAt = current.PublishAt
+		if current.Status != models.ArticleStatusPublished || ar.PublishAt == nil {
+			now := time.Now()
+			ar.PublishAt = &now
+		}
+	case models.ArticleStatusDraft:
+		ar.PublishAt = publishAt
+	case models.ArticleStatusArchived:
+		ar.PublishAt = current.PublishAt
+	default:
+		return utility.ErrBadParamInput.WithDetails(models.ErrorDetail{
+			Field:   "status",
+			Rule:    "oneof",
+			Message: "must be one of draft, scheduled, published or archived",
+		})
+	}
+	return nil
+}
+
+// version finds the given version of an article, the current one or a revision
+func (a *ArticleServiceImpl) version(ctx context.Context, current models.Article, version int64) (models.ArticleRevision, error) {
+	if version == current.Version {
+		return models.ArticleRevision{
+			ArticleID: current.ID,
+			Version:   current.Version,
+			Title:     current.Title,
+			Content:   current.Content,
+		}, nil
+	}
+	return a.revisionRepo.GetByVersion(ctx, current.ID, version)
+}
+
+// authorizeArticle lets the author of the article, or an editor, act on it
+func authorizeArticle(ctx context.Context, ar models.Article) (models.AuthUser, error) {
+	caller, ok := utility.AuthUserFromContext(ctx)
+	if !ok {
+		return models.AuthUser{}, utility.ErrUnauthorized
+	}
+	if caller.HasPermission(models.PermissionArticleManage) {
+		return caller, nil
+	}
+	if ar.Author == nil || ar.Author.ID != caller.ID {
+		return models.AuthUser{}, utility.ErrForbidden
+	}
+	return caller, nil
+}
+
+// normalizeTags trims and lowercases the tags and drops the empty and repeated ones,
+// a nil list stays nil
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
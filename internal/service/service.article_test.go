@@ -26,12 +26,12 @@ func TestFetch(t *testing.T) {
 	mockListArtilce = append(mockListArtilce, mockArticle)
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
+		mockArticleRepo.On("Fetch", mock.Anything, models.ArticleFilter{Status: models.ArticleStatusPublished}, mock.AnythingOfType("string"),
 			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, err := u.Fetch(context.TODO(), models.ArticleFilter{}, cursor, num)
 		cursorExpected := "next-cursor"
 		assert.Equal(t, cursorExpected, nextCursor)
 		assert.NotEmpty(t, nextCursor)
@@ -42,13 +42,13 @@ func TestFetch(t *testing.T) {
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
+		mockArticleRepo.On("Fetch", mock.Anything, models.ArticleFilter{Status: models.ArticleStatusPublished}, mock.AnythingOfType("string"),
 			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, err := u.Fetch(context.TODO(), models.ArticleFilter{}, cursor, num)
 
 		assert.Empty(t, nextCursor)
 		assert.Error(t, err)
@@ -57,6 +57,100 @@ func TestFetch(t *testing.T) {
 
 	})
 
+	t.Run("success-tags", func(t *testing.T) {
+		filter := models.ArticleFilter{Tags: []string{"go", "echo"}, Status: models.ArticleStatusPublished}
+		mockArticleRepo.On("Fetch", mock.Anything, filter, "", int64(10)).
+			Return(mockListArtilce, "next-cursor", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		list, _, err := u.Fetch(context.TODO(), models.ArticleFilter{Tags: []string{" Go", "echo", "go", ""}}, "", 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, len(mockListArtilce))
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	filter := models.ArticleFilter{AuthorID: 7}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, models.ArticleFilter{AuthorID: 7, Status: models.ArticleStatusPublished}, "", int64(10)).
+			Return([]models.Article{}, "", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), mockUserRepo, time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), filter, "", 0)
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("own-drafts", func(t *testing.T) {
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, filter, "", int64(10)).Return([]models.Article{}, "", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+		_, _, err := u.Fetch(ctx, filter, "", 0)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("drafts-of-others", func(t *testing.T) {
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{ID: 7}, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), mockUserRepo, time.Second*2)
+		ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8})
+
+		_, _, err := u.Fetch(ctx, models.ArticleFilter{AuthorID: 7, Status: models.ArticleStatusDraft}, "", 0)
+
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("unknown-author", func(t *testing.T) {
+		mockUserRepo.On("GetByID", mock.Anything, int64(7)).Return(models.User{}, utility.ErrNotFound).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), mockUserRepo, time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), filter, "", 0)
+
+		assert.Equal(t, utility.ErrNotFound, err)
+		mockUserRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockResult := models.ArticleSearchResult{
+		Article: models.Article{ID: 3, Title: "Clean Architecture", Content: "Golang <clean> architecture"},
+		Score:   1.5,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("Search", mock.Anything, "clean", "", int64(10)).
+			Return([]models.ArticleSearchResult{mockResult}, "next-cursor", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		list, nextCursor, err := u.Search(context.TODO(), " clean ", "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", nextCursor)
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, "<mark>Clean</mark> Architecture", list[0].Highlight.Title)
+			assert.Equal(t, "Golang &lt;<mark>clean</mark>&gt; architecture", list[0].Highlight.Snippet)
+		}
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("empty-query", func(t *testing.T) {
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, _, err := u.Search(context.TODO(), " ?! ", "", 0)
+
+		assert.Equal(t, utility.ErrBadParamInput, err)
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -64,12 +158,13 @@ func TestGetByID(t *testing.T) {
 	mockArticle := models.Article{
 		Title:   "Hello",
 		Content: "Content",
+		Status:  models.ArticleStatusPublished,
 	}
 
 	t.Run("success", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -82,7 +177,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("error-failed", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(models.Article{}, errors.New("Unexpected")).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -106,33 +201,48 @@ func TestStore(t *testing.T) {
 		Title:   "Hello",
 		Content: "Content",
 	}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
 
 	t.Run("success", func(t *testing.T) {
 		tempMockArticle := mockArticleParam
 
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(models.Article{}, utility.ErrNotFound).Once()
-		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Article")).Return(nil).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Author != nil && ar.Author.ID == 7
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Article).ID = 12
+		}).Return(nil).Once()
+		stored := models.Article{ID: 12, Title: "Hello", Content: "Content", Version: 1, Author: &models.ArticleAuthor{ID: 7, Name: "Author"}}
+		mockArticleRepo.On("GetByID", mock.Anything, int64(12)).Return(stored, nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Store(context.TODO(), tempMockArticle)
+		res, err := u.Store(ctx, tempMockArticle)
 
 		assert.NoError(t, err)
-		assert.Equal(t, mockArticleParam.Title, tempMockArticle.Title)
+		assert.Equal(t, stored, res)
 		mockArticleRepo.AssertExpectations(t)
 	})
 	t.Run("existing-title", func(t *testing.T) {
 		existingArticle := mockArticle
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(existingArticle, nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Store(context.TODO(), mockArticleParam)
+		_, err := u.Store(ctx, mockArticleParam)
 
 		assert.Error(t, err)
 		mockArticleRepo.AssertExpectations(t)
 
 	})
+	t.Run("unauthenticated", func(t *testing.T) {
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Store(context.TODO(), mockArticleParam)
+
+		assert.Equal(t, utility.ErrUnauthorized, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
 
 }
 
@@ -141,27 +251,52 @@ func TestDelete(t *testing.T) {
 	mockArticle := models.Article{
 		Title:   "Hello",
 		Content: "Content",
+		Author:  &models.ArticleAuthor{ID: 7, Name: "Author"},
 	}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
 
 	t.Run("success", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 
 		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(ctx, mockArticle.ID)
 
 		assert.NoError(t, err)
 		mockArticleRepo.AssertExpectations(t)
 
 	})
+	t.Run("success-editor", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
+
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+		editor := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionArticleManage}})
+
+		err := u.Delete(editor, mockArticle.ID)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("not-the-author", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
+
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+		other := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8, Permissions: []string{models.PermissionArticleDelete}})
+
+		err := u.Delete(other, mockArticle.ID)
+
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
 	t.Run("article-is-not-exist", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(models.Article{}, nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(ctx, mockArticle.ID)
 
 		assert.Error(t, err)
 		mockArticleRepo.AssertExpectations(t)
@@ -170,9 +305,9 @@ func TestDelete(t *testing.T) {
 	t.Run("error-happens-in-db", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(models.Article{}, errors.New("Unexpected Error")).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(ctx, mockArticle.ID)
 
 		assert.Error(t, err)
 		mockArticleRepo.AssertExpectations(t)
@@ -187,15 +322,240 @@ func TestUpdate(t *testing.T) {
 		Title:   "Hello",
 		Content: "Content",
 		ID:      23,
+		Version: 1,
+	}
+	mockArticle := models.Article{
+		ID:      23,
+		Title:   "Hello",
+		Content: "Content",
+		Version: 2,
+		Status:  models.ArticleStatusDraft,
+		Author:  &models.ArticleAuthor{ID: 7, Name: "Author"},
 	}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Article")).Once().Return(nil)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticleParam.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Article"), int64(7)).Once().Return(nil)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticleParam.ID).Return(mockArticle, nil).Once()
 
-		u := service.NewArticleService(mockArticleRepo, time.Second*2)
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
 
-		err := u.Update(context.TODO(), mockArticleParam)
+		a, err := u.Update(ctx, mockArticleParam)
 		assert.NoError(t, err)
+		assert.Equal(t, mockArticle.Version, a.Version)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("version-conflict", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticleParam.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Article"), int64(7)).Once().Return(utility.ErrVersionConflict)
+
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		a, err := u.Update(ctx, mockArticleParam)
+		assert.Equal(t, utility.ErrVersionConflict, err)
+		assert.Equal(t, models.Article{}, a)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("not-the-author", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticleParam.ID).Return(mockArticle, nil).Once()
+
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+		other := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8, Permissions: []string{models.PermissionArticleUpdate}})
+
+		_, err := u.Update(other, mockArticleParam)
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockRevisionRepo := new(mocks.ArticleRevisionRepository)
+	current := models.Article{ID: 23, Title: "Hello", Content: "one\nthree", Version: 3, Status: models.ArticleStatusPublished}
+	revision := models.ArticleRevision{ArticleID: 23, Version: 1, Title: "Hello", Content: "one\ntwo"}
+
+	mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(current, nil).Once()
+	mockRevisionRepo.On("GetByVersion", mock.Anything, int64(23), int64(1)).Return(revision, nil).Once()
+	u := service.NewArticleService(mockArticleRepo, mockRevisionRepo, new(mocks.UserRepository), time.Second*2)
+
+	diff, err := u.Diff(context.TODO(), 23, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DiffLine{{Op: models.DiffEqual, Text: "Hello"}}, diff.Title)
+	assert.Equal(t, []models.DiffLine{
+		{Op: models.DiffEqual, Text: "one"},
+		{Op: models.DiffDelete, Text: "two"},
+		{Op: models.DiffInsert, Text: "three"},
+	}, diff.Content)
+	mockArticleRepo.AssertExpectations(t)
+	mockRevisionRepo.AssertExpectations(t)
+}
+
+func TestRestore(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockRevisionRepo := new(mocks.ArticleRevisionRepository)
+	current := models.Article{ID: 23, Title: "Hello", Content: "Changed", Version: 3, Author: &models.ArticleAuthor{ID: 7}}
+	revision := models.ArticleRevision{ArticleID: 23, Version: 1, Title: "Hello", Content: "Original"}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(current, nil).Twice()
+		mockRevisionRepo.On("GetByVersion", mock.Anything, int64(23), int64(1)).Return(revision, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Content == "Original" && ar.Version == 3 && ar.Tags == nil
+		}), int64(7)).Return(nil).Once()
+		u := service.NewArticleService(mockArticleRepo, mockRevisionRepo, new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Restore(ctx, 23, 1, 3)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+	t.Run("not-the-author", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(current, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, mockRevisionRepo, new(mocks.UserRepository), time.Second*2)
+		other := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8})
+
+		_, err := u.Restore(other, 23, 1, 3)
+
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("stale-version", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(current, nil).Once()
+		mockRevisionRepo.On("GetByVersion", mock.Anything, int64(23), int64(1)).Return(revision, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Version == 2
+		}), int64(7)).Return(utility.ErrVersionConflict).Once()
+		u := service.NewArticleService(mockArticleRepo, mockRevisionRepo, new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Restore(ctx, 23, 1, 2)
+
+		assert.Equal(t, utility.ErrVersionConflict, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetByIDDraft(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	draft := models.Article{ID: 23, Title: "Hello", Status: models.ArticleStatusDraft, Author: &models.ArticleAuthor{ID: 7}}
+	mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(draft, nil)
+	u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+	_, err := u.GetByID(context.TODO(), 23)
+	assert.Equal(t, utility.ErrNotFound, err)
+
+	a, err := u.GetByID(utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7}), 23)
+	assert.NoError(t, err)
+	assert.Equal(t, draft, a)
+
+	_, err = u.GetByID(utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionArticleManage}}), 23)
+	assert.NoError(t, err)
+}
+
+func TestStoreStatus(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	t.Run("draft-by-default", func(t *testing.T) {
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(models.Article{}, utility.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Status == models.ArticleStatusDraft && ar.PublishAt == nil
+		})).Return(nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(models.Article{}, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Store(ctx, service.ArticleParam{Title: "Hello", Content: "Content"})
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("published", func(t *testing.T) {
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(models.Article{}, utility.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Status == models.ArticleStatusPublished && ar.PublishAt != nil
+		})).Return(nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(models.Article{}, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.Store(ctx, service.ArticleParam{Title: "Hello", Content: "Content", Status: models.ArticleStatusPublished})
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("scheduled-in-the-past", func(t *testing.T) {
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(models.Article{}, utility.ErrNotFound).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+		past := time.Now().Add(-time.Hour)
+
+		_, err := u.Store(ctx, service.ArticleParam{Title: "Hello", Content: "Content", Status: models.ArticleStatusScheduled, PublishAt: &past})
+
+		assert.True(t, errors.Is(err, utility.ErrBadParamInput))
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+
+	t.Run("own", func(t *testing.T) {
+		mockArticleRepo.On("Fetch", mock.Anything, models.ArticleFilter{Trashed: true, AuthorID: 7}, "", int64(10)).
+			Return([]models.Article{}, "", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, _, err := u.Trash(utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7}), "", 0)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("editor", func(t *testing.T) {
+		mockArticleRepo.On("Fetch", mock.Anything, models.ArticleFilter{Trashed: true}, "", int64(10)).
+			Return([]models.Article{}, "", nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+		editor := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionArticleManage}})
+
+		_, _, err := u.Trash(editor, "", 0)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestRestoreTrashed(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	trashed := models.Article{ID: 23, Title: "Hello", Author: &models.ArticleAuthor{ID: 7}}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("GetTrashedByID", mock.Anything, int64(23)).Return(trashed, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(models.Article{}, utility.ErrNotFound).Once()
+		mockArticleRepo.On("RestoreTrashed", mock.Anything, int64(23)).Return(nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(trashed, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.RestoreTrashed(ctx, 23)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("title-taken", func(t *testing.T) {
+		mockArticleRepo.On("GetTrashedByID", mock.Anything, int64(23)).Return(trashed, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(models.Article{ID: 24, Title: "Hello"}, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.RestoreTrashed(ctx, 23)
+
+		assert.Equal(t, utility.ErrConflict, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("not-the-author", func(t *testing.T) {
+		mockArticleRepo.On("GetTrashedByID", mock.Anything, int64(23)).Return(trashed, nil).Once()
+		u := service.NewArticleService(mockArticleRepo, new(mocks.ArticleRevisionRepository), new(mocks.UserRepository), time.Second*2)
+
+		_, err := u.RestoreTrashed(utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 8}), 23)
+
+		assert.Equal(t, utility.ErrForbidden, err)
 		mockArticleRepo.AssertExpectations(t)
 	})
 }
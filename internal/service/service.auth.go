@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kecci/goscription/internal/repository/mysql"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// AuthService represent the service of the authentication
+	AuthService interface {
+		Login(ctx context.Context, email, plain string) (res models.Token, err error)
+		Refresh(ctx context.Context, refreshToken string) (res models.Token, err error)
+		Logout(ctx context.Context, refreshToken string) (err error)
+		ParseAccessToken(accessToken string) (res models.AuthUser, err error)
+	}
+
+	// AuthServiceImpl represent the service of the authentication
+	AuthServiceImpl struct {
+		userService    UserService
+		tokenRepo      mysql.RefreshTokenRepository
+		roleRepo       mysql.RoleRepository
+		secret         []byte
+		issuer         string
+		accessTTL      time.Duration
+		refreshTTL     time.Duration
+		contextTimeout time.Duration
+	}
+)
+
+// AccessClaims is the payload of an access token. Permissions are resolved when
+// the token is issued, so role changes take effect on the next refresh.
+type AccessClaims struct {
+	Email       string   `json:"email"`
+	Permissions []string `json:"permissions,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// NewAuthService will create new an authService object representation of service.AuthService interface
+func NewAuthService(u UserService, r mysql.RefreshTokenRepository, rr mysql.RoleRepository, config models.Config, timeout time.Duration) AuthService {
+	if u == nil {
+		panic("User service is nil")
+	}
+	if r == nil {
+		panic("Refresh token repository is nil")
+	}
+	if rr == nil {
+		panic("Role repository is nil")
+	}
+	if config.Auth.Secret == "" {
+		panic("Auth secret is empty")
+	}
+
+	accessTTL := time.Duration(config.Auth.AccessTokenTTL) * time.Second
+	if accessTTL == 0 {
+		accessTTL = 15 * time.Minute
+	}
+	refreshTTL := time.Duration(config.Auth.RefreshTokenTTL) * time.Second
+	if refreshTTL == 0 {
+		refreshTTL = 14 * 24 * time.Hour
+	}
+
+	return &AuthServiceImpl{
+		userService:    u,
+		tokenRepo:      r,
+		roleRepo:       rr,
+		secret:         []byte(config.Auth.Secret),
+		issuer:         config.Auth.Issuer,
+		accessTTL:      accessTTL,
+		refreshTTL:     refreshTTL,
+		contextTimeout: timeout,
+	}
+}
+
+// Login ...
+func (a *AuthServiceImpl) Login(c context.Context, email, plain string) (res models.Token, err error) {
+	c, span := utility.StartSpan(c, "AuthService.Login")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userService.Authenticate(ctx, email, plain)
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	return a.issue(ctx, user)
+}
+
+// Refresh rotates a refresh token: the presented one is revoked and a new pair is issued.
+// Presenting a token that was already revoked is treated as theft, and every
+// refresh token of its owner gets revoked.
+func (a *AuthServiceImpl) Refresh(c context.Context, refreshToken string) (res models.Token, err error) {
+	c, span := utility.StartSpan(c, "AuthService.Refresh")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.tokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, utility.ErrNotFound) {
+		return models.Token{}, utility.ErrUnauthorized
+	}
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	if stored.RevokedAt != nil {
+		utility.Logger(ctx).Warnf("revoked refresh token %d reused, revoking every token of user %d", stored.ID, stored.UserID)
+		if err = a.tokenRepo.RevokeByUser(ctx, stored.UserID); err != nil {
+			return models.Token{}, err
+		}
+		return models.Token{}, utility.ErrUnauthorized
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return models.Token{}, utility.ErrUnauthorized
+	}
+
+	err = a.tokenRepo.Revoke(ctx, stored.ID)
+	if errors.Is(err, utility.ErrNotFound) {
+		// lost the race against a concurrent refresh of the same token
+		return models.Token{}, utility.ErrUnauthorized
+	}
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	user, err := a.userService.GetByID(ctx, stored.UserID)
+	if errors.Is(err, utility.ErrNotFound) {
+		return models.Token{}, utility.ErrUnauthorized
+	}
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	return a.issue(ctx, user)
+}
+
+// Logout revokes the given refresh token, unknown or already revoked tokens are ignored
+func (a *AuthServiceImpl) Logout(c context.Context, refreshToken string) (err error) {
+	c, span := utility.StartSpan(c, "AuthService.Logout")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.tokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, utility.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = a.tokenRepo.Revoke(ctx, stored.ID)
+	if errors.Is(err, utility.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// ParseAccessToken validates the signature and expiry of an access token
+func (a *AuthServiceImpl) ParseAccessToken(accessToken string) (res models.AuthUser, err error) {
+	claims := &AccessClaims{}
+	_, err = jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, utility.ErrUnauthorized
+		}
+		return a.secret, nil
+	})
+	if err != nil {
+		return models.AuthUser{}, utility.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return models.AuthUser{}, utility.ErrUnauthorized
+	}
+
+	return models.AuthUser{
+		ID:          id,
+		Email:       claims.Email,
+		Permissions: claims.Permissions,
+	}, nil
+}
+
+func (a *AuthServiceImpl) issue(ctx context.Context, user models.User) (res models.Token, err error) {
+	permissions, err := a.roleRepo.GetPermissionsByUser(ctx, user.ID)
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	now := time.Now()
+	claims := AccessClaims{
+		Email:       user.Email,
+		Permissions: permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(user.ID, 10),
+			Issuer:    a.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.accessTTL)),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.secret)
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return models.Token{}, err
+	}
+	err = a.tokenRepo.Store(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(a.refreshTTL),
+	})
+	if err != nil {
+		return models.Token{}, err
+	}
+
+	return models.Token{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(a.accessTTL / time.Second),
+	}, nil
+}
+
+// newOpaqueToken returns a random url-safe token
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is what gets stored instead of the token itself
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+var authConfig = models.Config{
+	Auth: models.Auth{
+		Secret:          "secret",
+		Issuer:          "goscription",
+		AccessTokenTTL:  60,
+		RefreshTokenTTL: 3600,
+	},
+}
+
+func TestLogin(t *testing.T) {
+	mockUser := models.User{ID: 7, Email: "hello@example.com"}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockRoleRepo := new(mocks.RoleRepository)
+		mockUserService.On("Authenticate", mock.Anything, mockUser.Email, "s3cret").Return(mockUser, nil).Once()
+		mockRoleRepo.On("GetPermissionsByUser", mock.Anything, mockUser.ID).Return([]string{models.PermissionArticleCreate}, nil).Once()
+		mockTokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(rt *models.RefreshToken) bool {
+			return rt.UserID == mockUser.ID && len(rt.TokenHash) == 64
+		})).Return(nil).Once()
+
+		u := service.NewAuthService(mockUserService, mockTokenRepo, mockRoleRepo, authConfig, time.Second*2)
+
+		token, err := u.Login(context.TODO(), mockUser.Email, "s3cret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.EqualValues(t, 60, token.ExpiresIn)
+		assert.NotEmpty(t, token.RefreshToken)
+
+		authUser, err := u.ParseAccessToken(token.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, models.AuthUser{ID: mockUser.ID, Email: mockUser.Email, Permissions: []string{models.PermissionArticleCreate}}, authUser)
+		assert.True(t, authUser.HasPermission(models.PermissionArticleCreate))
+		assert.False(t, authUser.HasPermission(models.PermissionArticleDelete))
+		mockUserService.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+	t.Run("invalid-credential", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockRoleRepo := new(mocks.RoleRepository)
+		mockUserService.On("Authenticate", mock.Anything, mockUser.Email, "wrong").Return(models.User{}, utility.ErrInvalidCredential).Once()
+
+		u := service.NewAuthService(mockUserService, mockTokenRepo, mockRoleRepo, authConfig, time.Second*2)
+
+		_, err := u.Login(context.TODO(), mockUser.Email, "wrong")
+
+		assert.Equal(t, utility.ErrInvalidCredential, err)
+		mockTokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	mockUser := models.User{ID: 7, Email: "hello@example.com"}
+
+	t.Run("rotate", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockRoleRepo := new(mocks.RoleRepository)
+		stored := models.RefreshToken{ID: 3, UserID: mockUser.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		mockTokenRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(stored, nil).Once()
+		mockTokenRepo.On("Revoke", mock.Anything, stored.ID).Return(nil).Once()
+		mockUserService.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockRoleRepo.On("GetPermissionsByUser", mock.Anything, mockUser.ID).Return([]string{}, nil).Once()
+		mockTokenRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+		u := service.NewAuthService(mockUserService, mockTokenRepo, mockRoleRepo, authConfig, time.Second*2)
+
+		token, err := u.Refresh(context.TODO(), "old-token")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "old-token", token.RefreshToken)
+		mockUserService.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+	t.Run("reused-token-revokes-family", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockRoleRepo := new(mocks.RoleRepository)
+		revokedAt := time.Now().Add(-time.Minute)
+		stored := models.RefreshToken{ID: 3, UserID: mockUser.ID, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+		mockTokenRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(stored, nil).Once()
+		mockTokenRepo.On("RevokeByUser", mock.Anything, mockUser.ID).Return(nil).Once()
+
+		u := service.NewAuthService(mockUserService, mockTokenRepo, mockRoleRepo, authConfig, time.Second*2)
+
+		_, err := u.Refresh(context.TODO(), "old-token")
+
+		assert.Equal(t, utility.ErrUnauthorized, err)
+		mockTokenRepo.AssertExpectations(t)
+	})
+	t.Run("expired", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockRoleRepo := new(mocks.RoleRepository)
+		stored := models.RefreshToken{ID: 3, UserID: mockUser.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+		mockTokenRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(stored, nil).Once()
+
+		u := service.NewAuthService(mockUserService, mockTokenRepo, mockRoleRepo, authConfig, time.Second*2)
+
+		_, err := u.Refresh(context.TODO(), "old-token")
+
+		assert.Equal(t, utility.ErrUnauthorized, err)
+		mockTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestParseAccessToken(t *testing.T) {
+	u := service.NewAuthService(new(mocks.UserService), new(mocks.RefreshTokenRepository), new(mocks.RoleRepository), authConfig, time.Second*2)
+
+	_, err := u.ParseAccessToken("not-a-token")
+	assert.Equal(t, utility.ErrUnauthorized, err)
+}
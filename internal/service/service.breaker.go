@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// BreakerService represent the service of the outbound circuit breakers
+	BreakerService interface {
+		Fetch(ctx context.Context) (res []models.BreakerStatus, err error)
+	}
+
+	// BreakerServiceImpl represent the service of the outbound circuit breakers
+	BreakerServiceImpl struct {
+	}
+)
+
+// NewBreakerService will create new a breakerService object representation of service.BreakerService interface
+func NewBreakerService() BreakerService {
+	return &BreakerServiceImpl{}
+}
+
+// Fetch ...
+func (BreakerServiceImpl) Fetch(ctx context.Context) (res []models.BreakerStatus, err error) {
+	return utility.BreakerStatuses(), nil
+}
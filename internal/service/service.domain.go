@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kecci/goscription/internal/outbound"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// DomainService represent the service of the domain
+	DomainService interface {
+		GetDomainAvailable(ctx context.Context, domain string) (models.DomainAvailableResponse, error)
+		GetDomainsAvailable(ctx context.Context, domains []string) (models.DomainAvailableBulkResponse, error)
+	}
+
+	// DomainServiceImpl represent the service of the domain
+	DomainServiceImpl struct {
+		godaddy     outbound.GodaddyOutbound
+		concurrency int
+		bulkLimit   int
+	}
+)
+
+const (
+	// busyRetries is how many times a domain is sent while the breaker is busy
+	busyRetries = 5
+	// busyBackoff is the first wait before sending it again, doubled each time
+	busyBackoff = 50 * time.Millisecond
+)
+
+// domainPattern accepts dot separated LDH labels, with at least one dot
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// NewDomainService will create new a domainService object representation of service.DomainService interface
+func NewDomainService(g outbound.GodaddyOutbound, config models.Config) DomainService {
+	if g == nil {
+		panic("Godaddy outbound is nil")
+	}
+
+	concurrency := config.Godaddy.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+	bulkLimit := config.Godaddy.BulkLimit
+	if bulkLimit <= 0 {
+		bulkLimit = 500
+	}
+
+	return &DomainServiceImpl{
+		godaddy:     g,
+		concurrency: concurrency,
+		bulkLimit:   bulkLimit,
+	}
+}
+
+// GetDomainAvailable ...
+func (d *DomainServiceImpl) GetDomainAvailable(ctx context.Context, domain string) (res models.DomainAvailableResponse, err error) {
+	ctx, span := utility.StartSpan(ctx, "DomainService.GetDomainAvailable")
+	defer utility.EndSpan(span, &err)
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if len(domain) > 253 || !domainPattern.MatchString(domain) {
+		return models.DomainAvailableResponse{}, utility.ErrBadParamInput
+	}
+	return d.godaddy.GetDomainAvailable(ctx, domain)
+}
+
+// GetDomainsAvailable checks every domain through a bounded pool of workers and
+// keeps the results in the order they were given. Once the breaker opens, the
+// domains not checked yet are answered with the breaker error instead of being sent.
+// Domains turned away because the breaker has too many calls in flight are tried again.
+func (d *DomainServiceImpl) GetDomainsAvailable(ctx context.Context, domains []string) (res models.DomainAvailableBulkResponse, err error) {
+	ctx, span := utility.StartSpan(ctx, "DomainService.GetDomainsAvailable")
+	defer utility.EndSpan(span, &err)
+
+	if len(domains) == 0 || len(domains) > d.bulkLimit {
+		return models.DomainAvailableBulkResponse{}, utility.ErrBadParamInput
+	}
+
+	results := make([]models.DomainAvailableResult, len(domains))
+	var open int32
+
+	// skip reports whether the batch has to stop, and why
+	skip := func() error {
+		if atomic.LoadInt32(&open) == 1 {
+			return utility.ErrServiceUnavailable
+		}
+		return ctx.Err()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	workers := d.concurrency
+	if workers > len(domains) {
+		workers = len(domains)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := skip(); err != nil {
+					results[i] = models.DomainAvailableResult{Domain: domains[i], Error: err.Error()}
+					continue
+				}
+				var err error
+				results[i], err = d.checkWhenFree(ctx, domains[i], skip)
+				if errors.Is(err, utility.ErrServiceUnavailable) {
+					atomic.StoreInt32(&open, 1)
+				}
+			}
+		}()
+	}
+
+	for i := range domains {
+		if err := skip(); err != nil {
+			results[i] = models.DomainAvailableResult{Domain: domains[i], Error: err.Error()}
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i] = models.DomainAvailableResult{Domain: domains[i], Error: ctx.Err().Error()}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return models.DomainAvailableBulkResponse{
+		Results: results,
+		Partial: atomic.LoadInt32(&open) == 1 || ctx.Err() != nil,
+	}, nil
+}
+
+// checkWhenFree checks the domain, backing off while the breaker is busy with other calls
+func (d *DomainServiceImpl) checkWhenFree(ctx context.Context, domain string, skip func() error) (models.DomainAvailableResult, error) {
+	wait := busyBackoff
+	for attempt := 1; ; attempt++ {
+		res, err := d.check(ctx, domain)
+		if !errors.Is(err, utility.ErrDependencyBusy) || attempt >= busyRetries {
+			return res, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return models.DomainAvailableResult{Domain: domain, Error: ctx.Err().Error()}, ctx.Err()
+		case <-timer.C:
+		}
+		if err = skip(); err != nil {
+			return models.DomainAvailableResult{Domain: domain, Error: err.Error()}, err
+		}
+		wait *= 2
+	}
+}
+
+func (d *DomainServiceImpl) check(ctx context.Context, domain string) (models.DomainAvailableResult, error) {
+	res, err := d.GetDomainAvailable(ctx, domain)
+	if err != nil {
+		return models.DomainAvailableResult{Domain: domain, Error: err.Error()}, err
+	}
+	return models.DomainAvailableResult{
+		Domain:    res.Domain,
+		Available: res.Available,
+		Price:     res.Price,
+		Currency:  res.Currency,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+func TestGetDomainAvailable(t *testing.T) {
+	mockGodaddy := new(mocks.GodaddyOutbound)
+	mockResponse := models.DomainAvailableResponse{Available: true, Domain: "example.com"}
+
+	t.Run("success", func(t *testing.T) {
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "example.com").Return(mockResponse, nil).Once()
+
+		u := service.NewDomainService(mockGodaddy, models.Config{})
+
+		res, err := u.GetDomainAvailable(context.TODO(), " Example.COM ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockResponse, res)
+		mockGodaddy.AssertExpectations(t)
+	})
+	t.Run("malformed-domain", func(t *testing.T) {
+		u := service.NewDomainService(mockGodaddy, models.Config{})
+
+		for _, domain := range []string{"", "example", "-example.com", "exa mple.com"} {
+			_, err := u.GetDomainAvailable(context.TODO(), domain)
+			assert.Equal(t, utility.ErrBadParamInput, err, domain)
+		}
+		mockGodaddy.AssertExpectations(t)
+	})
+}
+
+func TestGetDomainsAvailable(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockGodaddy := new(mocks.GodaddyOutbound)
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "example.com").Return(models.DomainAvailableResponse{Domain: "example.com", Available: true, Price: 11990000, Currency: "USD"}, nil).Once()
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "example.org").Return(models.DomainAvailableResponse{Domain: "example.org"}, nil).Once()
+
+		u := service.NewDomainService(mockGodaddy, models.Config{})
+
+		res, err := u.GetDomainsAvailable(context.TODO(), []string{"example.com", "example.org", "not a domain"})
+
+		assert.NoError(t, err)
+		assert.False(t, res.Partial)
+		assert.Equal(t, []models.DomainAvailableResult{
+			{Domain: "example.com", Available: true, Price: 11990000, Currency: "USD"},
+			{Domain: "example.org"},
+			{Domain: "not a domain", Error: utility.ErrBadParamInput.Error()},
+		}, res.Results)
+		mockGodaddy.AssertExpectations(t)
+	})
+	t.Run("breaker-opens-mid-batch", func(t *testing.T) {
+		mockGodaddy := new(mocks.GodaddyOutbound)
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "a.com").Return(models.DomainAvailableResponse{Domain: "a.com", Available: true}, nil).Once()
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "b.com").Return(models.DomainAvailableResponse{}, utility.ErrServiceUnavailable).Once()
+
+		u := service.NewDomainService(mockGodaddy, models.Config{Godaddy: models.Godaddy{Concurrency: 1}})
+
+		res, err := u.GetDomainsAvailable(context.TODO(), []string{"a.com", "b.com", "c.com", "d.com"})
+
+		assert.NoError(t, err)
+		assert.True(t, res.Partial)
+		assert.True(t, res.Results[0].Available)
+		for _, r := range res.Results[1:] {
+			assert.Equal(t, utility.ErrServiceUnavailable.Error(), r.Error, r.Domain)
+		}
+		mockGodaddy.AssertExpectations(t)
+	})
+	t.Run("busy-breaker-retried", func(t *testing.T) {
+		mockGodaddy := new(mocks.GodaddyOutbound)
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "a.com").Return(models.DomainAvailableResponse{}, utility.ErrDependencyBusy).Twice()
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "a.com").Return(models.DomainAvailableResponse{Domain: "a.com", Available: true}, nil).Once()
+		mockGodaddy.On("GetDomainAvailable", mock.Anything, "b.com").Return(models.DomainAvailableResponse{Domain: "b.com"}, nil).Once()
+
+		u := service.NewDomainService(mockGodaddy, models.Config{Godaddy: models.Godaddy{Concurrency: 1}})
+
+		res, err := u.GetDomainsAvailable(context.TODO(), []string{"a.com", "b.com"})
+
+		assert.NoError(t, err)
+		assert.False(t, res.Partial)
+		assert.Equal(t, []models.DomainAvailableResult{
+			{Domain: "a.com", Available: true},
+			{Domain: "b.com"},
+		}, res.Results)
+		mockGodaddy.AssertExpectations(t)
+	})
+	t.Run("over-limit", func(t *testing.T) {
+		u := service.NewDomainService(new(mocks.GodaddyOutbound), models.Config{Godaddy: models.Godaddy{BulkLimit: 1}})
+
+		_, err := u.GetDomainsAvailable(context.TODO(), []string{"a.com", "b.com"})
+		assert.Equal(t, utility.ErrBadParamInput, err)
+
+		_, err = u.GetDomainsAvailable(context.TODO(), nil)
+		assert.Equal(t, utility.ErrBadParamInput, err)
+	})
+}
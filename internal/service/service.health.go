@@ -1,22 +1,51 @@
 package service
 
-import "context"
+import (
+	"context"
+
+	"github.com/kecci/goscription/internal/health"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
 
 type (
-	// ArticleService represent the service of the article
+	// HealthService represent the service of the health probes
 	HealthService interface {
 		CheckHealth(ctx context.Context) (err error)
+		Liveness(ctx context.Context) (res models.Health)
+		Readiness(ctx context.Context) (res models.Health)
 	}
 
-	// ArticleServiceImpl represent the service of the article
+	// HealthServiceImpl represent the service of the health probes
 	HealthServiceImpl struct {
+		registry *health.Registry
 	}
 )
 
-func NewHealthService() HealthService {
-	return &HealthServiceImpl{}
+// NewHealthService will create new a healthService object representation of service.HealthService interface
+func NewHealthService(registry *health.Registry) HealthService {
+	if registry == nil {
+		panic("Health registry is nil")
+	}
+	return &HealthServiceImpl{
+		registry: registry,
+	}
 }
 
-func (HealthServiceImpl) CheckHealth(ctx context.Context) (err error) {
+// CheckHealth fails with ErrServiceUnavailable whenever the readiness probe is down
+func (h *HealthServiceImpl) CheckHealth(ctx context.Context) (err error) {
+	if h.Readiness(ctx).Status == models.HealthDown {
+		return utility.ErrServiceUnavailable
+	}
 	return nil
 }
+
+// Liveness only tells the process still serves requests, it checks no dependency
+func (h *HealthServiceImpl) Liveness(ctx context.Context) (res models.Health) {
+	return models.Health{Status: models.HealthUp, ShuttingDown: h.registry.Draining()}
+}
+
+// Readiness runs every registered checker
+func (h *HealthServiceImpl) Readiness(ctx context.Context) (res models.Health) {
+	return h.registry.Check(ctx)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kecci/goscription/internal/repository/mysql"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// RoleService represent the service of the role
+	RoleService interface {
+		Fetch(ctx context.Context) (res []models.Role, err error)
+		GetByUser(ctx context.Context, userID int64) (res []models.Role, err error)
+		Assign(ctx context.Context, userID int64, role string) (err error)
+		Revoke(ctx context.Context, userID int64, role string) (err error)
+	}
+
+	// RoleServiceImpl represent the service of the role
+	RoleServiceImpl struct {
+		roleRepo       mysql.RoleRepository
+		userRepo       mysql.UserRepository
+		contextTimeout time.Duration
+	}
+)
+
+// NewRoleService will create new a roleService object representation of service.RoleService interface
+func NewRoleService(r mysql.RoleRepository, u mysql.UserRepository, timeout time.Duration) RoleService {
+	return &RoleServiceImpl{
+		roleRepo:       r,
+		userRepo:       u,
+		contextTimeout: timeout,
+	}
+}
+
+// Fetch ...
+func (a *RoleServiceImpl) Fetch(c context.Context) (res []models.Role, err error) {
+	c, span := utility.StartSpan(c, "RoleService.Fetch")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.roleRepo.Fetch(ctx)
+}
+
+// GetByUser ...
+func (a *RoleServiceImpl) GetByUser(c context.Context, userID int64) (res []models.Role, err error) {
+	c, span := utility.StartSpan(c, "RoleService.GetByUser")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return a.roleRepo.GetByUser(ctx, userID)
+}
+
+// Assign ...
+func (a *RoleServiceImpl) Assign(c context.Context, userID int64, role string) (err error) {
+	c, span := utility.StartSpan(c, "RoleService.Assign")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return a.roleRepo.AssignRole(ctx, userID, role)
+}
+
+// Revoke ...
+func (a *RoleServiceImpl) Revoke(c context.Context, userID int64, role string) (err error) {
+	c, span := utility.StartSpan(c, "RoleService.Revoke")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.roleRepo.RevokeRole(ctx, userID, role)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kecci/goscription/internal/repository/mysql"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+type (
+	// TagService represent the service of the article tags
+	TagService interface {
+		Fetch(ctx context.Context) (res []models.Tag, err error)
+	}
+
+	// TagServiceImpl represent the service of the article tags
+	TagServiceImpl struct {
+		tagRepo        mysql.TagRepository
+		contextTimeout time.Duration
+	}
+)
+
+// NewTagService will create new a tagService object representation of service.TagService interface
+func NewTagService(t mysql.TagRepository, timeout time.Duration) TagService {
+	if t == nil {
+		panic("Tag repository is nil")
+	}
+	return &TagServiceImpl{
+		tagRepo:        t,
+		contextTimeout: timeout,
+	}
+}
+
+// Fetch lists the tags in use with the number of articles carrying each of them
+func (a *TagServiceImpl) Fetch(c context.Context) (res []models.Tag, err error) {
+	c, span := utility.StartSpan(c, "TagService.Fetch")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.tagRepo.Fetch(ctx)
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/kecci/goscription/internal/library/password"
+	"github.com/kecci/goscription/internal/metrics"
 	"github.com/kecci/goscription/internal/repository/mysql"
 	"github.com/kecci/goscription/models"
 	"github.com/kecci/goscription/utility"
@@ -12,17 +15,23 @@ import (
 type (
 	// UserService represent the service of the article
 	UserService interface {
-		// Fetch(ctx context.Context, cursor string, num int64) (res []models.Article, csr string, err error)
+		Fetch(ctx context.Context, filter models.UserFilter, cursor string, num int64) (res []models.User, csr string, err error)
 		GetByID(ctx context.Context, id int64) (res models.User, err error)
-		// Update(context.Context, ArticleParam) (err error)
+		Update(context.Context, UserPatchParam) (res models.User, err error)
+		UpdatePassword(ctx context.Context, id int64, oldPlain, newPlain string) (err error)
 		GetByEmail(ctx context.Context, email string) (res models.User, err error)
 		Store(context.Context, UserParam) (res models.User, err error)
-		// Delete(ctx context.Context, id int64) (err error)
+		Authenticate(ctx context.Context, email, plain string) (res models.User, err error)
+		Delete(ctx context.Context, id int64) (err error)
 	}
 
 	// UserServiceImpl represent the service of the article
 	UserServiceImpl struct {
 		userRepo       mysql.UserRepository
+		tokenRepo      mysql.RefreshTokenRepository
+		account        AccountService
+		hasher         password.Hasher
+		dummyHash      string
 		contextTimeout time.Duration
 	}
 )
@@ -30,21 +39,40 @@ type (
 // UserParam ...
 type UserParam struct {
 	ID       int64  `json:"id"`
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Name     string `json:"name" validate:"required,max=45"`
+	Email    string `json:"email" validate:"required,email,max=45"`
 	Password string `json:"password" validate:"required"`
 }
 
+// UserPatchParam carries the fields to change, nil fields are left as they are
+type UserPatchParam struct {
+	ID    int64
+	Name  *string
+	Email *string
+}
+
 // NewUserService will create new an articleService object representation of service.ArticleService interface
-func NewUserService(a mysql.UserRepository, timeout time.Duration) UserService {
+func NewUserService(a mysql.UserRepository, t mysql.RefreshTokenRepository, ac AccountService, h password.Hasher, timeout time.Duration) UserService {
+	// checked against when the email is unknown, so that a login takes as long either way
+	dummyHash, err := h.Hash("not the password of anyone")
+	if err != nil {
+		utility.Logger(context.Background()).Error(err)
+	}
 	return &UserServiceImpl{
 		userRepo:       a,
+		tokenRepo:      t,
+		account:        ac,
+		hasher:         h,
+		dummyHash:      dummyHash,
 		contextTimeout: timeout,
 	}
 }
 
 // Store ...
 func (a *UserServiceImpl) Store(c context.Context, p UserParam) (res models.User, err error) {
+	c, span := utility.StartSpan(c, "UserService.Store")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedUser, _ := a.GetByEmail(ctx, p.Email)
@@ -52,18 +80,55 @@ func (a *UserServiceImpl) Store(c context.Context, p UserParam) (res models.User
 		return models.User{}, utility.ErrConflict
 	}
 
+	hash, err := a.hasher.Hash(p.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
 	m := models.User{
 		Name:     p.Name,
 		Email:    p.Email,
-		Password: p.Password,
+		Password: hash,
+	}
+
+	err = a.userRepo.Store(ctx, &m, models.RoleReader)
+	if err != nil {
+		return models.User{}, err
+	}
+	metrics.UsersRegistered.Inc()
+
+	// the user can ask for another mail, a failed one must not fail the sign up
+	if err = a.account.SendVerification(ctx, m); err != nil {
+		utility.Logger(ctx).Error(err)
+	}
+	return m, nil
+}
+
+// Fetch ...
+func (a *UserServiceImpl) Fetch(c context.Context, filter models.UserFilter, cursor string, num int64) (res []models.User, nextCursor string, err error) {
+	c, span := utility.StartSpan(c, "UserService.Fetch")
+	defer utility.EndSpan(span, &err)
+
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.userRepo.Fetch(ctx, filter, cursor, num)
+	if err != nil {
+		return nil, "", err
 	}
 
-	err = a.userRepo.Store(ctx, &m)
 	return
 }
 
 // GetByID ...
 func (a *UserServiceImpl) GetByID(c context.Context, id int64) (res models.User, err error) {
+	c, span := utility.StartSpan(c, "UserService.GetByID")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
@@ -73,8 +138,180 @@ func (a *UserServiceImpl) GetByID(c context.Context, id int64) (res models.User,
 
 // GetByEmail ...
 func (a *UserServiceImpl) GetByEmail(c context.Context, email string) (res models.User, err error) {
+	c, span := utility.StartSpan(c, "UserService.GetByEmail")
+	defer utility.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	res, err = a.userRepo.GetByEmail(ctx, email)
 	return
 }
+
+// Authenticate checks the credential of a user, refuses users who have not verified
+// their email yet, and upgrades the stored hash when it was made with an outdated
+// algorithm or parameters
+func (a *UserServiceImpl) Authenticate(c context.Context, email, plain string) (res models.User, err error) {
+	c, span := utility.StartSpan(c, "UserService.Authenticate")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, utility.ErrNotFound) {
+		if a.dummyHash != "" {
+			_, _ = a.hasher.Verify(plain, a.dummyHash)
+		}
+		return models.User{}, utility.ErrInvalidCredential
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	ok, err := a.hasher.Verify(plain, res.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !ok {
+		return models.User{}, utility.ErrInvalidCredential
+	}
+	if res.EmailVerifiedAt == nil {
+		return models.User{}, utility.ErrEmailNotVerified
+	}
+
+	if a.hasher.NeedsRehash(res.Password) {
+		hash, err := a.hasher.Hash(plain)
+		if err != nil {
+			utility.Logger(ctx).Error(err)
+			return res, nil
+		}
+		// a failed upgrade must not block the login, the old hash is still valid
+		if err = a.userRepo.UpdatePassword(ctx, res.ID, hash); err != nil {
+			utility.Logger(ctx).Error(err)
+			return res, nil
+		}
+		res.Password = hash
+	}
+
+	return res, nil
+}
+
+// Update lets users edit themselves, and user managers edit anyone
+func (a *UserServiceImpl) Update(c context.Context, p UserPatchParam) (res models.User, err error) {
+	c, span := utility.StartSpan(c, "UserService.Update")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = authorizeUser(ctx, p.ID); err != nil {
+		return models.User{}, err
+	}
+
+	res, err = a.userRepo.GetByID(ctx, p.ID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if p.Name != nil {
+		res.Name = *p.Name
+	}
+	emailChanged := p.Email != nil && *p.Email != res.Email
+	if emailChanged {
+		existedUser, err := a.userRepo.GetByEmail(ctx, *p.Email)
+		if err != nil && !errors.Is(err, utility.ErrNotFound) {
+			return models.User{}, err
+		}
+		if existedUser != (models.User{}) {
+			return models.User{}, utility.ErrConflict
+		}
+		res.Email = *p.Email
+		// the new address has to be proven as well
+		res.EmailVerifiedAt = nil
+	}
+
+	if err = a.userRepo.Update(ctx, &res); err != nil {
+		return models.User{}, err
+	}
+
+	if emailChanged {
+		if err = a.account.SendVerification(ctx, res); err != nil {
+			utility.Logger(ctx).Error(err)
+		}
+	}
+	return res, nil
+}
+
+// UpdatePassword changes the password of the caller once the current one is confirmed,
+// and signs the user out of every other session
+func (a *UserServiceImpl) UpdatePassword(c context.Context, id int64, oldPlain, newPlain string) (err error) {
+	c, span := utility.StartSpan(c, "UserService.UpdatePassword")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	caller, ok := utility.AuthUserFromContext(ctx)
+	if !ok {
+		return utility.ErrUnauthorized
+	}
+	if caller.ID != id {
+		return utility.ErrForbidden
+	}
+
+	user, err := a.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ok, err = a.hasher.Verify(oldPlain, user.Password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return utility.ErrBadParamInput.WithDetails(models.ErrorDetail{
+			Field:   "old_password",
+			Rule:    "match",
+			Message: "does not match the current password",
+		})
+	}
+
+	hash, err := a.hasher.Hash(newPlain)
+	if err != nil {
+		return err
+	}
+	if err = a.userRepo.UpdatePassword(ctx, id, hash); err != nil {
+		return err
+	}
+	return a.tokenRepo.RevokeByUser(ctx, id)
+}
+
+// Delete soft deletes a user and revokes its refresh tokens, access tokens already
+// handed out stay valid until they expire
+func (a *UserServiceImpl) Delete(c context.Context, id int64) (err error) {
+	c, span := utility.StartSpan(c, "UserService.Delete")
+	defer utility.EndSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = authorizeUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err = a.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return a.tokenRepo.RevokeByUser(ctx, id)
+}
+
+// authorizeUser lets the user itself, or a user manager, act on the user with the given id
+func authorizeUser(ctx context.Context, id int64) (models.AuthUser, error) {
+	caller, ok := utility.AuthUserFromContext(ctx)
+	if !ok {
+		return models.AuthUser{}, utility.ErrUnauthorized
+	}
+	if caller.ID != id && !caller.HasPermission(models.PermissionUserManage) {
+		return models.AuthUser{}, utility.ErrForbidden
+	}
+	return caller, nil
+}
@@ -0,0 +1,276 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kecci/goscription/internal/library/password"
+	"github.com/kecci/goscription/internal/service"
+	"github.com/kecci/goscription/mocks"
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+)
+
+func newHasher(cost int) password.Hasher {
+	return password.NewHasher(models.Config{Password: models.Password{Algorithm: password.AlgorithmBcrypt, BcryptCost: cost}})
+}
+
+// countingHasher counts the passwords checked
+type countingHasher struct {
+	password.Hasher
+	verified int
+}
+
+func (h *countingHasher) Verify(plain, encoded string) (bool, error) {
+	h.verified++
+	return h.Hasher.Verify(plain, encoded)
+}
+
+func TestStoreUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAccount := new(mocks.AccountService)
+	hasher := newHasher(4)
+	mockUserParam := service.UserParam{
+		Name:     "Hello",
+		Email:    "hello@example.com",
+		Password: "s3cret",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUserParam.Email).Return(models.User{}, utility.ErrNotFound).Once()
+		mockUserRepo.On("Store", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			ok, _ := hasher.Verify(mockUserParam.Password, u.Password)
+			return ok
+		}), models.RoleReader).Return(nil).Once()
+		mockAccount.On("SendVerification", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.Email == mockUserParam.Email
+		})).Return(nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), mockAccount, hasher, time.Second*2)
+
+		res, err := u.Store(context.TODO(), mockUserParam)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUserParam.Email, res.Email)
+		assert.NotEqual(t, mockUserParam.Password, res.Password)
+		mockUserRepo.AssertExpectations(t)
+		mockAccount.AssertExpectations(t)
+	})
+	t.Run("existing-email", func(t *testing.T) {
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUserParam.Email).Return(models.User{ID: 1, Email: mockUserParam.Email}, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), mockAccount, hasher, time.Second*2)
+
+		_, err := u.Store(context.TODO(), mockUserParam)
+
+		assert.Equal(t, utility.ErrConflict, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	oldHash, err := newHasher(4).Hash("s3cret")
+	assert.NoError(t, err)
+	verifiedAt := time.Now()
+	mockUser := models.User{ID: 7, Email: "hello@example.com", Password: oldHash, EmailVerifiedAt: &verifiedAt}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		res, err := u.Authenticate(context.TODO(), mockUser.Email, "s3cret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.ID, res.ID)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("rehash-on-cost-change", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil).Once()
+		mockUserRepo.On("UpdatePassword", mock.Anything, mockUser.ID, mock.AnythingOfType("string")).Return(nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(5), time.Second*2)
+
+		res, err := u.Authenticate(context.TODO(), mockUser.Email, "s3cret")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, oldHash, res.Password)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("wrong-password", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), mockUser.Email, "wrong")
+
+		assert.Equal(t, utility.ErrInvalidCredential, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("email-not-verified", func(t *testing.T) {
+		unverified := mockUser
+		unverified.EmailVerifiedAt = nil
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(unverified, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), mockUser.Email, "s3cret")
+
+		assert.Equal(t, utility.ErrEmailNotVerified, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("unknown-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(models.User{}, utility.ErrNotFound).Once()
+		hasher := &countingHasher{Hasher: newHasher(4)}
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), hasher, time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), "nobody@example.com", "s3cret")
+
+		assert.Equal(t, utility.ErrInvalidCredential, err)
+		// a hash is still checked, an unknown email answers as slowly as a wrong password
+		assert.Equal(t, 1, hasher.verified)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	mockUser := models.User{ID: 7, Name: "Hello", Email: "hello@example.com"}
+	newEmail := "world@example.com"
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	t.Run("success", func(t *testing.T) {
+		newName := "World"
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Name == newName && u.Email == mockUser.Email
+		})).Return(nil).Once()
+		mockAccount := new(mocks.AccountService)
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), mockAccount, newHasher(4), time.Second*2)
+
+		res, err := u.Update(ctx, service.UserPatchParam{ID: mockUser.ID, Name: &newName})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newName, res.Name)
+		mockUserRepo.AssertExpectations(t)
+		mockAccount.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+	})
+	t.Run("email-change-needs-verification", func(t *testing.T) {
+		verifiedAt := time.Now()
+		verified := mockUser
+		verified.EmailVerifiedAt = &verifiedAt
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(verified, nil).Once()
+		mockUserRepo.On("GetByEmail", mock.Anything, newEmail).Return(models.User{}, utility.ErrNotFound).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Email == newEmail && u.EmailVerifiedAt == nil
+		})).Return(nil).Once()
+		mockAccount := new(mocks.AccountService)
+		mockAccount.On("SendVerification", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.Email == newEmail
+		})).Return(nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), mockAccount, newHasher(4), time.Second*2)
+
+		res, err := u.Update(ctx, service.UserPatchParam{ID: mockUser.ID, Email: &newEmail})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newEmail, res.Email)
+		assert.Nil(t, res.EmailVerifiedAt)
+		mockUserRepo.AssertExpectations(t)
+		mockAccount.AssertExpectations(t)
+	})
+	t.Run("existing-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("GetByEmail", mock.Anything, newEmail).Return(models.User{ID: 8, Email: newEmail}, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		_, err := u.Update(ctx, service.UserPatchParam{ID: mockUser.ID, Email: &newEmail})
+
+		assert.Equal(t, utility.ErrConflict, err)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+	t.Run("other-user", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		_, err := u.Update(ctx, service.UserPatchParam{ID: 8, Email: &newEmail})
+
+		assert.Equal(t, utility.ErrForbidden, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdatePassword(t *testing.T) {
+	oldHash, err := newHasher(4).Hash("s3cret")
+	assert.NoError(t, err)
+	mockUser := models.User{ID: 7, Email: "hello@example.com", Password: oldHash}
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 7})
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("UpdatePassword", mock.Anything, mockUser.ID, mock.AnythingOfType("string")).Return(nil).Once()
+		mockTokenRepo.On("RevokeByUser", mock.Anything, mockUser.ID).Return(nil).Once()
+
+		u := service.NewUserService(mockUserRepo, mockTokenRepo, new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		err := u.UpdatePassword(ctx, mockUser.ID, "s3cret", "n3w-s3cret")
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+	t.Run("wrong-old-password", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+
+		u := service.NewUserService(mockUserRepo, new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		err := u.UpdatePassword(ctx, mockUser.ID, "wrong", "n3w-s3cret")
+
+		assert.True(t, errors.Is(err, utility.ErrBadParamInput))
+		mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("other-user", func(t *testing.T) {
+		manager := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionUserManage}})
+
+		u := service.NewUserService(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), new(mocks.AccountService), newHasher(4), time.Second*2)
+
+		err := u.UpdatePassword(manager, mockUser.ID, "s3cret", "n3w-s3cret")
+
+		assert.Equal(t, utility.ErrForbidden, err)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	mockUserRepo.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+	mockTokenRepo.On("RevokeByUser", mock.Anything, int64(7)).Return(nil).Once()
+
+	u := service.NewUserService(mockUserRepo, mockTokenRepo, new(mocks.AccountService), newHasher(4), time.Second*2)
+	ctx := utility.WithAuthUser(context.TODO(), models.AuthUser{ID: 1, Permissions: []string{models.PermissionUserManage}})
+
+	err := u.Delete(ctx, 7)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/kecci/goscription/models"
 	mock "github.com/stretchr/testify/mock"
@@ -28,13 +29,13 @@ func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]models.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *ArticleRepository) Fetch(ctx context.Context, filter models.ArticleFilter, cursor string, num int64) ([]models.Article, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
 
 	var r0 []models.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []models.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, models.ArticleFilter, string, int64) []models.Article); ok {
+		r0 = rf(ctx, filter, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Article)
@@ -42,15 +43,15 @@ func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, models.ArticleFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, models.ArticleFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -100,6 +101,113 @@ func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (mode
 	return r0, r1
 }
 
+// GetTrashedByID provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) GetTrashedByID(ctx context.Context, id int64) (models.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, now
+func (_m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, deletedBefore
+func (_m *ArticleRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTrashed provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) RestoreTrashed(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, q, cursor, num
+func (_m *ArticleRepository) Search(ctx context.Context, q string, cursor string, num int64) ([]models.ArticleSearchResult, string, error) {
+	ret := _m.Called(ctx, q, cursor, num)
+
+	var r0 []models.ArticleSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []models.ArticleSearchResult); ok {
+		r0 = rf(ctx, q, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ArticleSearchResult)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, q, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, q, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: ctx, a
 func (_m *ArticleRepository) Store(ctx context.Context, a *models.Article) error {
 	ret := _m.Called(ctx, a)
@@ -114,13 +222,13 @@ func (_m *ArticleRepository) Store(ctx context.Context, a *models.Article) error
 	return r0
 }
 
-// Update provides a mock function with given fields: ctx, article
-func (_m *ArticleRepository) Update(ctx context.Context, article *models.Article) error {
-	ret := _m.Called(ctx, article)
+// Update provides a mock function with given fields: ctx, article, editorID
+func (_m *ArticleRepository) Update(ctx context.Context, article *models.Article, editorID int64) error {
+	ret := _m.Called(ctx, article, editorID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Article) error); ok {
-		r0 = rf(ctx, article)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article, int64) error); ok {
+		r0 = rf(ctx, article, editorID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/kecci/goscription/models"
 	mock "github.com/stretchr/testify/mock"
@@ -30,13 +31,34 @@ func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) ([]models.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Diff provides a mock function with given fields: ctx, id, from, to
+func (_m *ArticleService) Diff(ctx context.Context, id int64, from int64, to int64) (models.ArticleDiff, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	var r0 models.ArticleDiff
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) models.ArticleDiff); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(models.ArticleDiff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *ArticleService) Fetch(ctx context.Context, filter models.ArticleFilter, cursor string, num int64) ([]models.Article, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
 
 	var r0 []models.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []models.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, models.ArticleFilter, string, int64) []models.Article); ok {
+		r0 = rf(ctx, filter, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Article)
@@ -44,15 +66,15 @@ func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) (
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, models.ArticleFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, models.ArticleFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -102,30 +124,218 @@ func (_m *ArticleService) GetByTitle(ctx context.Context, title string) (models.
 	return r0, r1
 }
 
+// PublishDue provides a mock function with given fields: ctx
+func (_m *ArticleService) PublishDue(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeTrash provides a mock function with given fields: ctx, retention
+func (_m *ArticleService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	ret := _m.Called(ctx, retention)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int64); ok {
+		r0 = rf(ctx, retention)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, rev, version
+func (_m *ArticleService) Restore(ctx context.Context, id int64, rev int64, version int64) (models.Article, error) {
+	ret := _m.Called(ctx, id, rev, version)
+
+	var r0 models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) models.Article); ok {
+		r0 = rf(ctx, id, rev, version)
+	} else {
+		r0 = ret.Get(0).(models.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, rev, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTrashed provides a mock function with given fields: ctx, id
+func (_m *ArticleService) RestoreTrashed(ctx context.Context, id int64) (models.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revisions provides a mock function with given fields: ctx, id, cursor, num
+func (_m *ArticleService) Revisions(ctx context.Context, id int64, cursor string, num int64) ([]models.ArticleRevision, string, error) {
+	ret := _m.Called(ctx, id, cursor, num)
+
+	var r0 []models.ArticleRevision
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int64) []models.ArticleRevision); ok {
+		r0 = rf(ctx, id, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ArticleRevision)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, int64) string); ok {
+		r1 = rf(ctx, id, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, string, int64) error); ok {
+		r2 = rf(ctx, id, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Search provides a mock function with given fields: ctx, q, cursor, num
+func (_m *ArticleService) Search(ctx context.Context, q string, cursor string, num int64) ([]models.ArticleSearchResult, string, error) {
+	ret := _m.Called(ctx, q, cursor, num)
+
+	var r0 []models.ArticleSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []models.ArticleSearchResult); ok {
+		r0 = rf(ctx, q, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ArticleSearchResult)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, q, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, q, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: _a0, _a1
-func (_m *ArticleService) Store(_a0 context.Context, _a1 service.ArticleParam) error {
+func (_m *ArticleService) Store(_a0 context.Context, _a1 service.ArticleParam) (models.Article, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, service.ArticleParam) error); ok {
+	var r0 models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, service.ArticleParam) models.Article); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.Article)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, service.ArticleParam) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Trash provides a mock function with given fields: ctx, cursor, num
+func (_m *ArticleService) Trash(ctx context.Context, cursor string, num int64) ([]models.Article, string, error) {
+	ret := _m.Called(ctx, cursor, num)
+
+	var r0 []models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []models.Article); ok {
+		r0 = rf(ctx, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Article)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
+		r1 = rf(ctx, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // Update provides a mock function with given fields: _a0, _a1
-func (_m *ArticleService) Update(_a0 context.Context, _a1 service.ArticleParam) error {
+func (_m *ArticleService) Update(_a0 context.Context, _a1 service.ArticleParam) (models.Article, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, service.ArticleParam) error); ok {
+	var r0 models.Article
+	if rf, ok := ret.Get(0).(func(context.Context, service.ArticleParam) models.Article); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.Article)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, service.ArticleParam) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	models "github.com/kecci/goscription/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,20 +14,20 @@ type GodaddyOutbound struct {
 	mock.Mock
 }
 
-// GetDomainAvailable provides a mock function with given fields: domain
-func (_m *GodaddyOutbound) GetDomainAvailable(domain string) (models.DomainAvailableResponse, error) {
-	ret := _m.Called(domain)
+// GetDomainAvailable provides a mock function with given fields: ctx, domain
+func (_m *GodaddyOutbound) GetDomainAvailable(ctx context.Context, domain string) (models.DomainAvailableResponse, error) {
+	ret := _m.Called(ctx, domain)
 
 	var r0 models.DomainAvailableResponse
-	if rf, ok := ret.Get(0).(func(string) models.DomainAvailableResponse); ok {
-		r0 = rf(domain)
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.DomainAvailableResponse); ok {
+		r0 = rf(ctx, domain)
 	} else {
 		r0 = ret.Get(0).(models.DomainAvailableResponse)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(domain)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, domain)
 	} else {
 		r1 = ret.Error(1)
 	}
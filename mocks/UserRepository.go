@@ -14,6 +14,50 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UserRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *UserRepository) Fetch(ctx context.Context, filter models.UserFilter, cursor string, num int64) ([]models.User, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []models.User
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter, string, int64) []models.User); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, models.UserFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, models.UserFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *UserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
 	ret := _m.Called(ctx, email)
@@ -56,13 +100,55 @@ func (_m *UserRepository) GetByID(ctx context.Context, id int64) (models.User, e
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: ctx, a
-func (_m *UserRepository) Store(ctx context.Context, a *models.User) error {
-	ret := _m.Called(ctx, a)
+// MarkEmailVerified provides a mock function with given fields: ctx, id
+func (_m *UserRepository) MarkEmailVerified(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, a, role
+func (_m *UserRepository) Store(ctx context.Context, a *models.User, role string) error {
+	ret := _m.Called(ctx, a, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, string) error); ok {
+		r0 = rf(ctx, a, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, u
+func (_m *UserRepository) Update(ctx context.Context, u *models.User) error {
+	ret := _m.Called(ctx, u)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, a)
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, hash
+func (_m *UserRepository) UpdatePassword(ctx context.Context, id int64, hash string) error {
+	ret := _m.Called(ctx, id, hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, hash)
 	} else {
 		r0 = ret.Error(0)
 	}
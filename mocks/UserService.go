@@ -16,6 +16,71 @@ type UserService struct {
 	mock.Mock
 }
 
+// Authenticate provides a mock function with given fields: ctx, email, plain
+func (_m *UserService) Authenticate(ctx context.Context, email string, plain string) (models.User, error) {
+	ret := _m.Called(ctx, email, plain)
+
+	var r0 models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.User); ok {
+		r0 = rf(ctx, email, plain)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, plain)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UserService) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *UserService) Fetch(ctx context.Context, filter models.UserFilter, cursor string, num int64) ([]models.User, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []models.User
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter, string, int64) []models.User); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, models.UserFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, models.UserFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *UserService) GetByEmail(ctx context.Context, email string) (models.User, error) {
 	ret := _m.Called(ctx, email)
@@ -59,12 +124,54 @@ func (_m *UserService) GetByID(ctx context.Context, id int64) (models.User, erro
 }
 
 // Store provides a mock function with given fields: _a0, _a1
-func (_m *UserService) Store(_a0 context.Context, _a1 service.UserParam) error {
+func (_m *UserService) Store(_a0 context.Context, _a1 service.UserParam) (models.User, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, service.UserParam) error); ok {
+	var r0 models.User
+	if rf, ok := ret.Get(0).(func(context.Context, service.UserParam) models.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, service.UserParam) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *UserService) Update(_a0 context.Context, _a1 service.UserPatchParam) (models.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 models.User
+	if rf, ok := ret.Get(0).(func(context.Context, service.UserPatchParam) models.User); ok {
 		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, service.UserPatchParam) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, oldPlain, newPlain
+func (_m *UserService) UpdatePassword(ctx context.Context, id int64, oldPlain string, newPlain string) error {
+	ret := _m.Called(ctx, id, oldPlain, newPlain)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, id, oldPlain, newPlain)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,11 +4,60 @@ import (
 	"time"
 )
 
-// Article represent the Article contract
+const (
+	// ArticleStatusDraft is only visible to its author and the editors
+	ArticleStatusDraft = "draft"
+	// ArticleStatusScheduled gets published once its publish_at has passed
+	ArticleStatusScheduled = "scheduled"
+	// ArticleStatusPublished is visible to everyone
+	ArticleStatusPublished = "published"
+	// ArticleStatusArchived is withdrawn from the public, like a draft
+	ArticleStatusArchived = "archived"
+)
+
+// Article represent the Article contract, DeletedAt is set while it is in the trash
 type Article struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64          `json:"id"`
+	Title     string         `json:"title" validate:"required,max=45"`
+	Content   string         `json:"content" validate:"required"`
+	Version   int64          `json:"version"`
+	Status    string         `json:"status"`
+	PublishAt *time.Time     `json:"publish_at"`
+	Tags      []string       `json:"tags"`
+	Author    *ArticleAuthor `json:"author"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt *time.Time     `json:"deleted_at"`
+}
+
+// ArticleAuthor represent the public summary of the user who wrote an article. Articles
+// older than authorship, left without an owner by the backfill, have none.
+type ArticleAuthor struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArticleFilter narrows down an article listing, empty fields are ignored
+type ArticleFilter struct {
+	// Tags keeps the articles carrying every one of them
+	Tags []string `json:"tags"`
+	// AuthorID keeps the articles written by that user
+	AuthorID int64 `json:"author_id"`
+	// Status keeps the articles in that status
+	Status string `json:"status"`
+	// Trashed lists the trash instead of the live articles
+	Trashed bool `json:"trashed"`
+}
+
+// ArticleSearchResult represent an article matching a search, ranked by its relevance
+type ArticleSearchResult struct {
+	Article
+	Score     float64          `json:"score"`
+	Highlight ArticleHighlight `json:"highlight"`
+}
+
+// ArticleHighlight holds HTML escaped excerpts of an article, the matched terms wrapped in <mark>
+type ArticleHighlight struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	// DiffEqual marks a line both revisions share
+	DiffEqual = "equal"
+	// DiffInsert marks a line only the newer revision has
+	DiffInsert = "insert"
+	// DiffDelete marks a line only the older revision has
+	DiffDelete = "delete"
+)
+
+// ArticleRevision represent a past state of an article, kept when an update replaced it
+type ArticleRevision struct {
+	ID        int64  `json:"id"`
+	ArticleID int64  `json:"article_id"`
+	Version   int64  `json:"version"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	// EditedBy is the user whose update replaced this state
+	EditedBy *ArticleAuthor `json:"edited_by"`
+	EditedAt time.Time      `json:"edited_at"`
+}
+
+// DiffLine represent a line of a line-based diff
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// ArticleDiff represent the changes between two versions of an article
+type ArticleDiff struct {
+	ArticleID int64      `json:"article_id"`
+	From      int64      `json:"from"`
+	To        int64      `json:"to"`
+	Title     []DiffLine `json:"title"`
+	Content   []DiffLine `json:"content"`
+}
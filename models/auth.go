@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// AuthUser represent the caller identity carried by an access token
+type AuthUser struct {
+	ID          int64    `json:"id"`
+	Email       string   `json:"email"`
+	Permissions []string `json:"permissions"`
+}
+
+// HasPermission reports whether the caller was granted permission
+func (u AuthUser) HasPermission(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Token represent the pair of tokens handed out on login and refresh
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshToken represent a server-side refresh token, only its hash is stored
+type RefreshToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
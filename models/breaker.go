@@ -0,0 +1,26 @@
+package models
+
+// Breaker states as reported by the admin endpoint
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerStatus represent the health of one outbound dependency. Counters are
+// totals since the start, ErrorPercent and latencies cover the rolling window.
+type BreakerStatus struct {
+	Name              string  `json:"name"`
+	State             string  `json:"state"`
+	Requests          int64   `json:"requests"`
+	Successes         int64   `json:"successes"`
+	Failures          int64   `json:"failures"`
+	Timeouts          int64   `json:"timeouts"`
+	ShortCircuits     int64   `json:"short_circuits"`
+	Rejects           int64   `json:"rejects"`
+	FallbackSuccesses int64   `json:"fallback_successes"`
+	FallbackFailures  int64   `json:"fallback_failures"`
+	ErrorPercent      float64 `json:"error_percent"`
+	LatencyMeanMs     uint32  `json:"latency_mean_ms"`
+	LatencyP99Ms      uint32  `json:"latency_p99_ms"`
+}
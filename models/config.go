@@ -3,17 +3,50 @@ package models
 type (
 	// Config is application configuration
 	Config struct {
-		Title          string   `mapstructure:"title"`
-		Debug          bool     `mapstructure:"debug"`
-		ContextTimeout int      `mapstructure:"contextTimeout"`
-		Server         Server   `mapstructure:"server"`
-		Database       Database `mapstructure:"database"`
-		Godaddy        Godaddy  `mapstructure:"godaddy"`
+		Title          string             `mapstructure:"title"`
+		Debug          bool               `mapstructure:"debug"`
+		ContextTimeout int                `mapstructure:"contextTimeout"`
+		Server         Server             `mapstructure:"server"`
+		Log            Log                `mapstructure:"log"`
+		Tracing        Tracing            `mapstructure:"tracing"`
+		Database       Database           `mapstructure:"database"`
+		Godaddy        Godaddy            `mapstructure:"godaddy"`
+		Password       Password           `mapstructure:"password"`
+		Auth           Auth               `mapstructure:"auth"`
+		Mail           Mail               `mapstructure:"mail"`
+		Scheduler      Scheduler          `mapstructure:"scheduler"`
+		Health         HealthProbe        `mapstructure:"health"`
+		RateLimit      RateLimit          `mapstructure:"rateLimit"`
+		Breakers       map[string]Breaker `mapstructure:"breakers"`
 	}
 
-	// Server ...
+	// Server is the HTTP listener. TrustProxy takes the client IP out of X-Forwarded-For,
+	// only set it behind a proxy that overwrites the header.
 	Server struct {
-		Address string `mapstructure:"address"`
+		Address    string `mapstructure:"address"`
+		TrustProxy bool   `mapstructure:"trustProxy"`
+	}
+
+	// Log is the logging setup, Format is either text or json. The level is debug
+	// when Config.Debug is set and info otherwise.
+	Log struct {
+		Format string `mapstructure:"format"`
+	}
+
+	// Tracing is the OpenTelemetry setup. Exporter is either otlp, stdout or none,
+	// SampleRatio is the share of the new traces that are recorded, zero records them all.
+	Tracing struct {
+		Exporter    string  `mapstructure:"exporter"`
+		ServiceName string  `mapstructure:"serviceName"`
+		SampleRatio float64 `mapstructure:"sampleRatio"`
+		OTLP        OTLP    `mapstructure:"otlp"`
+	}
+
+	// OTLP is the collector the spans are sent to over HTTP
+	OTLP struct {
+		Endpoint string            `mapstructure:"endpoint"`
+		Insecure bool              `mapstructure:"insecure"`
+		Headers  map[string]string `mapstructure:"headers"`
 	}
 
 	// Database ...
@@ -30,5 +63,108 @@ type (
 	Godaddy struct {
 		Host          string `mapstructure:"host"`
 		Authorization string `mapstructure:"authorization"`
+		Concurrency   int    `mapstructure:"concurrency"`
+		BulkLimit     int    `mapstructure:"bulkLimit"`
+	}
+
+	// Password is the hashing setup for user passwords
+	Password struct {
+		Algorithm  string   `mapstructure:"algorithm"`
+		BcryptCost int      `mapstructure:"bcryptCost"`
+		Argon2id   Argon2id `mapstructure:"argon2id"`
+	}
+
+	// Auth is the token setup, TTLs are in seconds
+	Auth struct {
+		Secret          string `mapstructure:"secret"`
+		Issuer          string `mapstructure:"issuer"`
+		AccessTokenTTL  int    `mapstructure:"accessTokenTTL"`
+		RefreshTokenTTL int    `mapstructure:"refreshTokenTTL"`
+	}
+
+	// Mail is the outgoing mail setup. Driver is either smtp, file or log, links in the
+	// mails point to BaseURL and token TTLs are in seconds.
+	Mail struct {
+		Driver         string `mapstructure:"driver"`
+		From           string `mapstructure:"from"`
+		BaseURL        string `mapstructure:"baseURL"`
+		Dir            string `mapstructure:"dir"`
+		VerifyTokenTTL int    `mapstructure:"verifyTokenTTL"`
+		ResetTokenTTL  int    `mapstructure:"resetTokenTTL"`
+		SMTP           SMTP   `mapstructure:"smtp"`
+	}
+
+	// Scheduler is the setup of the background jobs, intervals and the retention of
+	// the trashed articles are in seconds
+	Scheduler struct {
+		PublishInterval int `mapstructure:"publishInterval"`
+		PurgeInterval   int `mapstructure:"purgeInterval"`
+		TrashRetention  int `mapstructure:"trashRetention"`
+	}
+
+	// HealthProbe is the probe setup in milliseconds. Timeout bounds each dependency check,
+	// DrainDelay is how long readiness fails before the server stops accepting requests.
+	HealthProbe struct {
+		Timeout    int `mapstructure:"timeout"`
+		DrainDelay int `mapstructure:"drainDelay"`
+	}
+
+	// RateLimit is the per client rate limiting. Store is either memory or mysql, the
+	// latter shares the budgets across replicas. Clients are told apart by user id, or
+	// by IP when anonymous. A key sent in the header named APIKeyHeader gets a budget
+	// of its own, spent on top of the first one.
+	RateLimit struct {
+		Enabled      bool             `mapstructure:"enabled"`
+		Store        string           `mapstructure:"store"`
+		APIKeyHeader string           `mapstructure:"apiKeyHeader"`
+		Default      RateLimitRule    `mapstructure:"default"`
+		Routes       []RateLimitRoute `mapstructure:"routes"`
+	}
+
+	// RateLimitRule lets Burst requests through at once, refilled at Requests per
+	// Period seconds. Zero requests means no limit.
+	RateLimitRule struct {
+		Requests int `mapstructure:"requests"`
+		Period   int `mapstructure:"period"`
+		Burst    int `mapstructure:"burst"`
+	}
+
+	// RateLimitRoute is the rule of one route template, such as POST /articles
+	RateLimitRoute struct {
+		Method        string `mapstructure:"method"`
+		Path          string `mapstructure:"path"`
+		RateLimitRule `mapstructure:",squash"`
+	}
+
+	// SMTP ...
+	SMTP struct {
+		Host     string `mapstructure:"host"`
+		Port     string `mapstructure:"port"`
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password"`
+	}
+
+	// Breaker is the circuit breaker and retry setup of one outbound dependency,
+	// durations are in milliseconds
+	Breaker struct {
+		Timeout                int   `mapstructure:"timeout"`
+		AttemptTimeout         int   `mapstructure:"attemptTimeout"`
+		MaxConcurrentRequests  int   `mapstructure:"maxConcurrentRequests"`
+		RequestVolumeThreshold int   `mapstructure:"requestVolumeThreshold"`
+		ErrorPercentThreshold  int   `mapstructure:"errorPercentThreshold"`
+		SleepWindow            int   `mapstructure:"sleepWindow"`
+		Retries                int   `mapstructure:"retries"`
+		BackoffInitial         int   `mapstructure:"backoffInitial"`
+		BackoffMax             int   `mapstructure:"backoffMax"`
+		RetryableStatus        []int `mapstructure:"retryableStatus"`
+	}
+
+	// Argon2id ...
+	Argon2id struct {
+		Time       uint32 `mapstructure:"time"`
+		Memory     uint32 `mapstructure:"memory"`
+		Threads    uint8  `mapstructure:"threads"`
+		KeyLength  uint32 `mapstructure:"keyLength"`
+		SaltLength uint32 `mapstructure:"saltLength"`
 	}
 )
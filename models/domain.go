@@ -9,3 +9,25 @@ type DomainAvailableResponse struct {
 	Period     int32  `json:"period"`
 	Price      int32  `json:"price"`
 }
+
+// DomainAvailableRequest represent the bulk domain available request
+type DomainAvailableRequest struct {
+	Domains []string `json:"domains" validate:"required"`
+}
+
+// DomainAvailableResult represent the outcome of one domain in a bulk check
+type DomainAvailableResult struct {
+	Domain    string `json:"domain"`
+	Available bool   `json:"available"`
+	Price     int32  `json:"price,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DomainAvailableBulkResponse represent the bulk domain available response.
+// Partial is set when some domains were not checked, because the breaker opened
+// or the request was cancelled mid-batch.
+type DomainAvailableBulkResponse struct {
+	Results []DomainAvailableResult `json:"results"`
+	Partial bool                    `json:"partial"`
+}
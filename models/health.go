@@ -0,0 +1,25 @@
+package models
+
+// Health states of a probe and of its components
+const (
+	HealthUp       = "up"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// Health is the answer of a probe. A probe is down when a critical component is down
+// or the application is shutting down, and degraded when only other components are.
+type Health struct {
+	Status       string            `json:"status"`
+	ShuttingDown bool              `json:"shutting_down,omitempty"`
+	Components   []ComponentHealth `json:"components,omitempty"`
+}
+
+// ComponentHealth is the result of the check of one dependency
+type ComponentHealth struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// Problem represent an error response, following RFC 7807 (application/problem+json)
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Instance string        `json:"instance,omitempty"`
+	Code     string        `json:"code"`
+	Errors   []ErrorDetail `json:"errors,omitempty"`
+}
+
+// ErrorDetail points at one faulty part of the request, Field is empty when it is not
+// about a single field and Rule names the validation rule that failed
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,36 @@
+package models
+
+const (
+	// RoleReader is granted to every registered user
+	RoleReader = "reader"
+	// RoleAuthor can write articles
+	RoleAuthor = "author"
+	// RoleEditor can write and remove any article
+	RoleEditor = "editor"
+	// RoleAdmin can do everything
+	RoleAdmin = "admin"
+)
+
+const (
+	// PermissionArticleCreate allows to create articles
+	PermissionArticleCreate = "article:create"
+	// PermissionArticleUpdate allows to edit articles
+	PermissionArticleUpdate = "article:update"
+	// PermissionArticleDelete allows to remove articles
+	PermissionArticleDelete = "article:delete"
+	// PermissionArticleManage allows to edit and remove the articles of other authors
+	PermissionArticleManage = "article:manage"
+	// PermissionUserRead allows to look up other users
+	PermissionUserRead = "user:read"
+	// PermissionUserManage allows to manage users and their roles
+	PermissionUserManage = "user:manage"
+	// PermissionSystemRead allows to look at the operational state of the service
+	PermissionSystemRead = "system:read"
+)
+
+// Role represent the Role contract
+type Role struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Tag represent a label shared by any number of articles
+type Tag struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	ArticleCount int64  `json:"article_count"`
+}
@@ -1,9 +1,19 @@
 package models
 
+import "time"
+
 // User represent the Article contract
 type User struct {
 	ID       int64  `json:"id"`
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Name     string `json:"name" validate:"required,max=45"`
+	Email    string `json:"email" validate:"required,email,max=45"`
+	Password string `json:"-" validate:"required"`
+	// EmailVerifiedAt stays nil until the user follows the verification mail
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// UserFilter narrows down a user listing, empty fields are ignored
+type UserFilter struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
 }
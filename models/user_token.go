@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	// TokenPurposeVerifyEmail is the purpose of the tokens proving the ownership of an email
+	TokenPurposeVerifyEmail = "verify_email"
+	// TokenPurposeResetPassword is the purpose of the tokens allowing to set a forgotten password
+	TokenPurposeResetPassword = "reset_password"
+)
+
+// UserToken represent a single-use token mailed to a user, only its hash is stored
+type UserToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package utility
+
+import (
+	"context"
+
+	"github.com/kecci/goscription/models"
+)
+
+type contextKey string
+
+const authUserKey contextKey = "auth_user"
+
+// WithAuthUser returns a copy of ctx carrying the authenticated caller
+func WithAuthUser(ctx context.Context, user models.AuthUser) context.Context {
+	return context.WithValue(ctx, authUserKey, user)
+}
+
+// AuthUserFromContext returns the authenticated caller, if any
+func AuthUserFromContext(ctx context.Context) (models.AuthUser, bool) {
+	user, ok := ctx.Value(authUserKey).(models.AuthUser)
+	return user, ok
+}
@@ -0,0 +1,206 @@
+package utility
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	metricCollector "github.com/afex/hystrix-go/hystrix/metric_collector"
+	"github.com/afex/hystrix-go/hystrix/rolling"
+	"github.com/kecci/goscription/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	outboundRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbound_requests_total",
+		Help: "Outbound calls per breaker and result.",
+	}, []string{"breaker", "result"})
+	outboundFallbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbound_fallbacks_total",
+		Help: "Fallback runs per breaker and result.",
+	}, []string{"breaker", "result"})
+	outboundDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "outbound_request_duration_seconds",
+		Help:    "Latency of the outbound calls that reached the dependency, retries included.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"breaker"})
+	breakerStateDesc = prometheus.NewDesc("outbound_breaker_state",
+		"Breaker state, 0 closed, 1 half-open, 2 open.", []string{"breaker"}, nil)
+	breakerErrorPercentDesc = prometheus.NewDesc("outbound_breaker_error_percent",
+		"Error percentage over the rolling window the breaker trips on.", []string{"breaker"}, nil)
+
+	breakers      = map[string]*breakerCollector{}
+	breakersMutex sync.RWMutex
+)
+
+func init() {
+	metricCollector.Registry.Register(collectorFor)
+}
+
+// BreakerCollectors are the prometheus collectors of the outbound calls and their breakers
+func BreakerCollectors() []prometheus.Collector {
+	return []prometheus.Collector{outboundRequests, outboundFallbacks, outboundDuration, breakerGauges{}}
+}
+
+// breakerCollector receives every hystrix execution of one breaker
+type breakerCollector struct {
+	mutex    sync.RWMutex
+	status   models.BreakerStatus
+	requests *rolling.Number
+	errors   *rolling.Number
+	latency  *rolling.Timing
+	// trials is the number of test calls let through the open circuit still running
+	trials int32
+}
+
+// collectorFor hands hystrix the collector of a breaker, creating it on first use
+func collectorFor(name string) metricCollector.MetricCollector {
+	breakersMutex.Lock()
+	defer breakersMutex.Unlock()
+
+	if c, ok := breakers[name]; ok {
+		return c
+	}
+	c := &breakerCollector{
+		status:   models.BreakerStatus{Name: name},
+		requests: rolling.NewNumber(),
+		errors:   rolling.NewNumber(),
+		latency:  rolling.NewTiming(),
+	}
+	breakers[name] = c
+	return c
+}
+
+// Update ...
+func (c *breakerCollector) Update(r metricCollector.MetricResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	name := c.status.Name
+	c.requests.Increment(r.Attempts)
+	c.errors.Increment(r.Errors)
+	c.status.Requests += int64(r.Attempts)
+	c.status.Successes += int64(r.Successes)
+	c.status.Failures += int64(r.Failures)
+	c.status.Timeouts += int64(r.Timeouts)
+	c.status.ShortCircuits += int64(r.ShortCircuits)
+	c.status.Rejects += int64(r.Rejects)
+	c.status.FallbackSuccesses += int64(r.FallbackSuccesses)
+	c.status.FallbackFailures += int64(r.FallbackFailures)
+
+	switch {
+	case r.Successes > 0:
+		outboundRequests.WithLabelValues(name, "success").Inc()
+	case r.Failures > 0:
+		outboundRequests.WithLabelValues(name, "failure").Inc()
+	case r.Timeouts > 0:
+		outboundRequests.WithLabelValues(name, "timeout").Inc()
+	case r.ShortCircuits > 0:
+		outboundRequests.WithLabelValues(name, "short_circuit").Inc()
+	case r.Rejects > 0:
+		outboundRequests.WithLabelValues(name, "rejected").Inc()
+	case r.ContextCanceled > 0:
+		outboundRequests.WithLabelValues(name, "context_canceled").Inc()
+	case r.ContextDeadlineExceeded > 0:
+		outboundRequests.WithLabelValues(name, "context_deadline_exceeded").Inc()
+	}
+	if r.FallbackSuccesses > 0 {
+		outboundFallbacks.WithLabelValues(name, "success").Inc()
+	}
+	if r.FallbackFailures > 0 {
+		outboundFallbacks.WithLabelValues(name, "failure").Inc()
+	}
+
+	// short circuits and rejects never reach the dependency
+	if r.ShortCircuits == 0 && r.Rejects == 0 {
+		c.latency.Add(r.RunDuration)
+		outboundDuration.WithLabelValues(name).Observe(r.RunDuration.Seconds())
+	}
+}
+
+// Reset is called by hystrix when the circuit closes again, only the rolling window is cleared
+func (c *breakerCollector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.requests = rolling.NewNumber()
+	c.errors = rolling.NewNumber()
+	c.latency = rolling.NewTiming()
+}
+
+func (c *breakerCollector) snapshot() models.BreakerStatus {
+	c.mutex.RLock()
+	status := c.status
+	now := time.Now()
+	if requests := c.requests.Sum(now); requests > 0 {
+		status.ErrorPercent = c.errors.Sum(now) / requests * 100
+	}
+	status.LatencyMeanMs = c.latency.Mean()
+	status.LatencyP99Ms = c.latency.Percentile(99)
+	c.mutex.RUnlock()
+
+	status.State = models.BreakerClosed
+	circuit, _, err := hystrix.GetCircuit(status.Name)
+	if err == nil && circuit.IsOpen() {
+		status.State = models.BreakerOpen
+		if atomic.LoadInt32(&c.trials) > 0 {
+			status.State = models.BreakerHalfOpen
+		}
+	}
+	return status
+}
+
+// startTrial marks the breaker half-open until the returned func is called. Asking
+// AllowRequest would use up the single test hystrix lets through, so the calls that
+// run while the circuit is open report themselves as that test.
+func startTrial(name string) func() {
+	c := collectorFor(name).(*breakerCollector)
+	atomic.AddInt32(&c.trials, 1)
+	return func() {
+		atomic.AddInt32(&c.trials, -1)
+	}
+}
+
+// BreakerStatuses reports every breaker known so far, sorted by name
+func BreakerStatuses() []models.BreakerStatus {
+	breakersMutex.RLock()
+	collectors := make([]*breakerCollector, 0, len(breakers))
+	for _, c := range breakers {
+		collectors = append(collectors, c)
+	}
+	breakersMutex.RUnlock()
+
+	res := make([]models.BreakerStatus, 0, len(collectors))
+	for _, c := range collectors {
+		res = append(res, c.snapshot())
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// breakerGauges exposes the state and the error percentage, both computed at scrape time
+type breakerGauges struct{}
+
+// Describe ...
+func (breakerGauges) Describe(ch chan<- *prometheus.Desc) {
+	ch <- breakerStateDesc
+	ch <- breakerErrorPercentDesc
+}
+
+// Collect ...
+func (breakerGauges) Collect(ch chan<- prometheus.Metric) {
+	for _, status := range BreakerStatuses() {
+		state := 0.0
+		switch status.State {
+		case models.BreakerHalfOpen:
+			state = 1
+		case models.BreakerOpen:
+			state = 2
+		}
+		ch <- prometheus.MustNewConstMetric(breakerStateDesc, prometheus.GaugeValue, state, status.Name)
+		ch <- prometheus.MustNewConstMetric(breakerErrorPercentDesc, prometheus.GaugeValue, status.ErrorPercent, status.Name)
+	}
+}
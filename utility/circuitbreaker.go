@@ -1,85 +1,256 @@
 package utility
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/afex/hystrix-go/hystrix"
-	"github.com/eapache/go-resiliency/retrier"
-	"github.com/sirupsen/logrus"
+	"github.com/kecci/goscription/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func CallUsingCircuitBreaker(breakername string, request *http.Request, body []byte) ([]byte, error) {
-	hystrix.ConfigureCommand(breakername, hystrix.CommandConfig{
-		Timeout:                5000,
-		SleepWindow:            5000,
-		RequestVolumeThreshold: 10,
+// BreakerClient calls one named outbound dependency through a hystrix circuit breaker,
+// retrying failed attempts with exponential backoff and jitter.
+type BreakerClient struct {
+	name           string
+	timeout        time.Duration
+	client         *http.Client
+	retries        int
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+	retryable      map[int]bool
+}
+
+// NewBreakerClient configures the breaker named after the dependency once, zero values fall back to defaults
+func NewBreakerClient(name string, config models.Breaker) *BreakerClient {
+	if config.Timeout <= 0 {
+		config.Timeout = 5000
+	}
+	if config.SleepWindow <= 0 {
+		config.SleepWindow = 5000
+	}
+	if config.RequestVolumeThreshold <= 0 {
+		config.RequestVolumeThreshold = 10
+	}
+	if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.BackoffInitial <= 0 {
+		config.BackoffInitial = 100
+	}
+	if config.BackoffMax < config.BackoffInitial {
+		config.BackoffMax = config.BackoffInitial
+	}
+	if len(config.RetryableStatus) == 0 {
+		config.RetryableStatus = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+
+	// known to the admin endpoint before its first call
+	collectorFor(name)
+
+	hystrix.ConfigureCommand(name, hystrix.CommandConfig{
+		Timeout:                config.Timeout,
+		MaxConcurrentRequests:  config.MaxConcurrentRequests,
+		RequestVolumeThreshold: config.RequestVolumeThreshold,
+		SleepWindow:            config.SleepWindow,
+		ErrorPercentThreshold:  config.ErrorPercentThreshold,
 	})
 
-	output := make(chan []byte, 1) // declare the channel where the hystrix goroutine will put success responses.
+	retryable := make(map[int]bool, len(config.RetryableStatus))
+	for _, status := range config.RetryableStatus {
+		retryable[status] = true
+	}
 
-	errors := hystrix.Go(breakername, // pass the name of the circuit breaker as first parameter.
+	return &BreakerClient{
+		name:           name,
+		timeout:        time.Duration(config.Timeout) * time.Millisecond,
+		client:         &http.Client{Timeout: time.Duration(config.AttemptTimeout) * time.Millisecond},
+		retries:        config.Retries,
+		backoffInitial: time.Duration(config.BackoffInitial) * time.Millisecond,
+		backoffMax:     time.Duration(config.BackoffMax) * time.Millisecond,
+		retryable:      retryable,
+	}
+}
 
-		// 2nd parameter, the inlined func to run inside the breaker.
-		func() error {
-			// for hystrix, forward the err from the retrier. it's nil if successful.
-			return CallWithRetries(request, output)
-		},
+// Name is the name of the breaker
+func (b *BreakerClient) Name() string {
+	return b.name
+}
 
-		// 3rd parameter, the fallback func. in this case, we just do a bit of logging and return the error.
-		func(err error) error {
-			logrus.Errorf("in fallback function for breaker %v, error: %v", breakername, err.Error())
-			circuit, _, _ := hystrix.GetCircuit(breakername)
-			logrus.Errorf("circuit state is: %v", circuit.IsOpen())
+// Do sends the request and returns the response status and body. Answers below 500 that
+// are not retryable are handed back along with their status, branching on it is up to the caller.
+// An open breaker is reported as ErrServiceUnavailable, a call rejected because too many
+// are in flight as ErrDependencyBusy, and a call past the breaker timeout as ErrGatewayTimeout.
+func (b *BreakerClient) Do(ctx context.Context, req *http.Request) (status int, body []byte, err error) {
+	ctx, span := StartSpan(ctx, "breaker "+b.name, trace.WithAttributes(attribute.String("breaker.name", b.name)))
+	defer EndSpan(span, &err)
+
+	return b.do(ctx, req)
+}
+
+func (b *BreakerClient) do(ctx context.Context, req *http.Request) (int, []byte, error) {
+	// the body has to be replayed on every attempt
+	if req.Body != nil && req.GetBody == nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return 0, nil, err
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	// stop the attempts in flight once hystrix gave up on the command
+	callCtx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	var (
+		status      int
+		out         []byte
+		fallbackErr error
+	)
+	err := hystrix.DoC(callCtx, b.name,
+		func(ctx context.Context) (err error) {
+			// hystrix only runs a command on an open circuit as its half-open test
+			if circuit, _, _ := hystrix.GetCircuit(b.name); circuit.IsOpen() {
+				defer startTrial(b.name)()
+			}
+			status, out, err = b.doWithRetries(ctx, req)
+			return err
+		},
+		func(ctx context.Context, err error) error {
+			circuit, _, _ := hystrix.GetCircuit(b.name)
+			Logger(ctx).Errorf("in fallback function for breaker %v, error: %v, circuit open: %v", b.name, err, circuit.IsOpen())
+			fallbackErr = err
 			return err
 		})
+	if err == nil {
+		return status, out, nil
+	}
+	// hystrix flattens the error of a failing fallback into a string, the one it was given is kept aside
+	if fallbackErr != nil {
+		err = fallbackErr
+	}
+	switch {
+	case errors.Is(err, hystrix.ErrCircuitOpen):
+		return 0, nil, ErrServiceUnavailable
+	case errors.Is(err, hystrix.ErrMaxConcurrency):
+		return 0, nil, ErrDependencyBusy
+	// the attempts may notice the breaker timeout before hystrix does
+	case errors.Is(err, hystrix.ErrTimeout), errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		return 0, nil, ErrGatewayTimeout
+	}
+	return 0, nil, err
+}
+
+func (b *BreakerClient) doWithRetries(ctx context.Context, req *http.Request) (int, []byte, error) {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var (
+			status     int
+			body       []byte
+			retryAfter time.Duration
+			retry      bool
+		)
+		status, body, retryAfter, retry, err = b.attempt(ctx, req)
+		if err == nil {
+			return status, body, nil
+		}
+		if !retry || attempt >= b.retries || ctx.Err() != nil {
+			break
+		}
 
-	// response and error handling. if the call was successful, the output channel gets the response. otherwise,
-	// the errors channel gives us the error.
-	select {
-	case out := <-output:
-		logrus.Debugf("call in breaker %v successful", breakername)
-		return out, nil
+		wait := b.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		Logger(ctx).Warnf("breaker %v attempt %v failed: %v, retrying in %v", b.name, attempt+1, err, wait)
 
-	case err := <-errors:
-		return nil, err
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
+	return 0, nil, err
 }
 
-func CallWithRetries(req *http.Request, output chan []byte) error {
-	// Retries Attempt
-	retries := 3
-
-	// create a retrier with constant backoff, retries number of attempts (3) with a 100ms sleep between retries.
-	r := retrier.New(retrier.ConstantBackoff(retries, 100*time.Millisecond), nil)
-
-	// this counter is just for getting some logging for showcasing, remove in production code.
-	attempt := 0
-
-	// retrier works similar to hystrix, we pass the actual work (doing the http request) in a func.
-	err := r.Run(func() error {
-		attempt++
-
-		// do http request and handle response. if successful, pass resp.body over output channel,
-		// otherwise, do a bit of error logging and return the err.
-		var client = &http.Client{}
-		resp, err := client.Do(req)
-		if err == nil && resp.StatusCode < 500 {
-			responsebody, err := ioutil.ReadAll(resp.Body)
-			if err == nil {
-				defer resp.Body.Close()
-				output <- responsebody
-				return nil
-			}
-			return err
-		} else if err == nil {
-			err = fmt.Errorf("status was %v", resp.StatusCode)
+// attempt sends the request once, the duration is the Retry-After asked by the server.
+// Transport errors and the configured statuses are worth another attempt, other 5xx only fail.
+func (b *BreakerClient) attempt(ctx context.Context, req *http.Request) (int, []byte, time.Duration, bool, error) {
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return 0, nil, 0, false, err
 		}
+		r.Body = body
+	}
 
-		logrus.Errorf("retrier failed, attempt %v", attempt)
-		return err
-	})
-	return err
+	ctx, span := StartSpan(ctx, "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(r)...),
+	)
+	defer span.End()
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	resp, err := b.client.Do(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, nil, 0, true, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(resp.StatusCode))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, 0, true, err
+	}
+	if retry := b.retryable[resp.StatusCode]; retry || resp.StatusCode >= http.StatusInternalServerError {
+		return 0, nil, parseRetryAfter(resp.Header.Get("Retry-After")), retry, fmt.Errorf("status was %v", resp.StatusCode)
+	}
+	return resp.StatusCode, body, 0, false, nil
+}
+
+// backoff is a full jitter exponential backoff
+func (b *BreakerClient) backoff(attempt int) time.Duration {
+	ceiling := b.backoffInitial << uint(attempt)
+	if ceiling > b.backoffMax || ceiling <= 0 {
+		ceiling = b.backoffMax
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// parseRetryAfter reads both the delay-seconds and the HTTP-date forms
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
 }
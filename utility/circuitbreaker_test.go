@@ -0,0 +1,220 @@
+package utility_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakerClientRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := utility.NewBreakerClient("test-retries", models.Breaker{Retries: 3, BackoffInitial: 1, BackoffMax: 5})
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, ioutil.NopCloser(bytes.NewBufferString("payload")))
+	assert.NoError(t, err)
+
+	status, res, err := client.Do(context.TODO(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", string(res))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestBreakerClientNotRetryable(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code":"INVALID"}`))
+	}))
+	defer server.Close()
+
+	client := utility.NewBreakerClient("test-not-retryable", models.Breaker{Retries: 3, BackoffInitial: 1})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	status, res, err := client.Do(context.TODO(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, `{"code":"INVALID"}`, string(res))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestBreakerClientUnlistedServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	client := utility.NewBreakerClient(fmt.Sprintf("test-unlisted-%d", time.Now().UnixNano()), models.Breaker{Retries: 3, BackoffInitial: 1})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, _, err = client.Do(context.TODO(), req)
+
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestBreakerClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := utility.NewBreakerClient(fmt.Sprintf("test-timeout-%d", time.Now().UnixNano()), models.Breaker{Timeout: 20})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, _, err = client.Do(context.TODO(), req)
+
+	assert.Equal(t, utility.ErrGatewayTimeout, err)
+}
+
+func TestBreakerClientContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := utility.NewBreakerClient("test-cancelled", models.Breaker{Retries: 3})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = client.Do(ctx, req)
+
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestBreakerStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	// breakers are global, keep the name unique across runs
+	name := fmt.Sprintf("test-statuses-%d", time.Now().UnixNano())
+	client := utility.NewBreakerClient(name, models.Breaker{
+		RequestVolumeThreshold: 1,
+		ErrorPercentThreshold:  1,
+		SleepWindow:            60000,
+		BackoffInitial:         1,
+	})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, _, err = client.Do(context.TODO(), req)
+	assert.Error(t, err)
+
+	status := func() models.BreakerStatus {
+		for _, s := range utility.BreakerStatuses() {
+			if s.Name == name {
+				return s
+			}
+		}
+		return models.BreakerStatus{}
+	}
+	// hystrix hands the executions to the collectors asynchronously
+	assert.Eventually(t, func() bool { return status().Failures == 1 }, time.Second, 10*time.Millisecond)
+
+	_, _, err = client.Do(context.TODO(), req)
+	assert.Equal(t, utility.ErrServiceUnavailable, err)
+
+	assert.Eventually(t, func() bool { return status().ShortCircuits == 1 }, time.Second, 10*time.Millisecond)
+	s := status()
+	assert.Equal(t, models.BreakerOpen, s.State)
+	assert.EqualValues(t, 2, s.Requests)
+	assert.EqualValues(t, 100, s.ErrorPercent)
+	assert.EqualValues(t, 2, s.FallbackFailures)
+	assert.Zero(t, s.FallbackSuccesses)
+}
+
+func TestBreakerStatusesHalfOpen(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	name := fmt.Sprintf("test-half-open-%d", time.Now().UnixNano())
+	client := utility.NewBreakerClient(name, models.Breaker{
+		RequestVolumeThreshold: 1,
+		ErrorPercentThreshold:  1,
+		SleepWindow:            20,
+	})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	status := func() models.BreakerStatus {
+		for _, s := range utility.BreakerStatuses() {
+			if s.Name == name {
+				return s
+			}
+		}
+		return models.BreakerStatus{}
+	}
+
+	_, _, err = client.Do(context.TODO(), req)
+	assert.Error(t, err)
+	assert.Eventually(t, func() bool { return status().State == models.BreakerOpen }, time.Second, 10*time.Millisecond)
+
+	// once the sleep window passed, the next call is the test of the half-open breaker
+	time.Sleep(30 * time.Millisecond)
+	done := make(chan error)
+	go func() {
+		_, _, err := client.Do(context.TODO(), req)
+		done <- err
+	}()
+	assert.Eventually(t, func() bool { return status().State == models.BreakerHalfOpen }, time.Second, 10*time.Millisecond)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Eventually(t, func() bool { return status().State == models.BreakerClosed }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,134 @@
+package utility
+
+import (
+	"strings"
+
+	"github.com/kecci/goscription/models"
+)
+
+// maxDiffCells bounds the comparisons of the longest common subsequence, beyond it
+// the changed middle of the texts is reported as replaced as a whole
+const maxDiffCells = 4 << 20
+
+// DiffLines compares two texts line by line, listing the lines of both in order
+// with the operation turning older into newer
+func DiffLines(older, newer string) []models.DiffLine {
+	a, b := splitLines(older), splitLines(newer)
+
+	// the common ends need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	res := make([]models.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		res = append(res, models.DiffLine{Op: models.DiffEqual, Text: line})
+	}
+	res = append(res, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		res = append(res, models.DiffLine{Op: models.DiffEqual, Text: line})
+	}
+	return res
+}
+
+func diffMiddle(a, b []string) []models.DiffLine {
+	res := make([]models.DiffLine, 0, len(a)+len(b))
+	if len(a)*len(b) > maxDiffCells {
+		res = appendLines(res, models.DiffDelete, a)
+		return appendLines(res, models.DiffInsert, b)
+	}
+	return hirschberg(res, a, b)
+}
+
+// hirschberg appends the diff of a and b along their longest common subsequence.
+// It splits a in halves and finds where b splits along, keeping only two rows of
+// lengths at a time, so the memory grows with the texts rather than their product.
+func hirschberg(res []models.DiffLine, a, b []string) []models.DiffLine {
+	switch {
+	case len(a) == 0:
+		return appendLines(res, models.DiffInsert, b)
+	case len(b) == 0:
+		return appendLines(res, models.DiffDelete, a)
+	case len(a) == 1:
+		for j, line := range b {
+			if line == a[0] {
+				res = appendLines(res, models.DiffInsert, b[:j])
+				res = append(res, models.DiffLine{Op: models.DiffEqual, Text: line})
+				return appendLines(res, models.DiffInsert, b[j+1:])
+			}
+		}
+		res = appendLines(res, models.DiffDelete, a)
+		return appendLines(res, models.DiffInsert, b)
+	}
+
+	mid := len(a) / 2
+	head := lcsLengths(a[:mid], b)
+	tail := lcsLengthsReversed(a[mid:], b)
+
+	// the first best split keeps deletions ahead of insertions
+	split := 0
+	for j := 1; j <= len(b); j++ {
+		if head[j]+tail[j] > head[split]+tail[split] {
+			split = j
+		}
+	}
+	res = hirschberg(res, a[:mid], b[:split])
+	return hirschberg(res, a[mid:], b[split:])
+}
+
+// lcsLengths gives, for every j, the length of the longest common subsequence of a and b[:j]
+func lcsLengths(a, b []string) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for _, line := range a {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case line == b[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsLengthsReversed gives, for every j, the length of the longest common subsequence of a and b[j:]
+func lcsLengthsReversed(a, b []string) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				cur[j] = prev[j+1] + 1
+			case prev[j] >= cur[j+1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+func appendLines(res []models.DiffLine, op string, lines []string) []models.DiffLine {
+	for _, line := range lines {
+		res = append(res, models.DiffLine{Op: op, Text: line})
+	}
+	return res
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
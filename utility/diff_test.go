@@ -0,0 +1,79 @@
+package utility_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	res := utility.DiffLines("a\nb\nc\nd", "a\nc\nx\nd")
+
+	assert.Equal(t, []models.DiffLine{
+		{Op: models.DiffEqual, Text: "a"},
+		{Op: models.DiffDelete, Text: "b"},
+		{Op: models.DiffEqual, Text: "c"},
+		{Op: models.DiffInsert, Text: "x"},
+		{Op: models.DiffEqual, Text: "d"},
+	}, res)
+}
+
+func TestDiffLinesEmpty(t *testing.T) {
+	assert.Equal(t, []models.DiffLine{{Op: models.DiffInsert, Text: "new"}}, utility.DiffLines("", "new"))
+	assert.Empty(t, utility.DiffLines("", ""))
+}
+
+func TestDiffLinesKeepsLongestCommonSubsequence(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := func() []string {
+		lines := make([]string, rnd.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return lines
+	}
+	// lcs is the plain quadratic table, as a reference
+	lcs := func(a, b []string) int {
+		table := make([][]int, len(a)+1)
+		for i := range table {
+			table[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				switch {
+				case a[i] == b[j]:
+					table[i][j] = table[i+1][j+1] + 1
+				case table[i+1][j] > table[i][j+1]:
+					table[i][j] = table[i+1][j]
+				default:
+					table[i][j] = table[i][j+1]
+				}
+			}
+		}
+		return table[0][0]
+	}
+
+	for n := 0; n < 500; n++ {
+		a, b := text(), text()
+		var older, newer []string
+		equal := 0
+		for _, line := range utility.DiffLines(strings.Join(a, "\n"), strings.Join(b, "\n")) {
+			switch line.Op {
+			case models.DiffEqual:
+				equal++
+				older, newer = append(older, line.Text), append(newer, line.Text)
+			case models.DiffDelete:
+				older = append(older, line.Text)
+			case models.DiffInsert:
+				newer = append(newer, line.Text)
+			}
+		}
+		assert.Equal(t, strings.Join(a, "\n"), strings.Join(older, "\n"))
+		assert.Equal(t, strings.Join(b, "\n"), strings.Join(newer, "\n"))
+		assert.Equal(t, lcs(a, b), equal, "%q %q", a, b)
+	}
+}
@@ -4,18 +4,89 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/sirupsen/logrus"
+	"github.com/kecci/goscription/models"
 )
 
+// AppError is an error the API knows how to answer with. Message and Details are shown
+// to the caller, the cause is only logged.
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Details []models.ErrorDetail
+	Err     error
+}
+
+// NewAppError ...
+func NewAppError(code string, status int, message string) *AppError {
+	return &AppError{Code: code, Status: status, Message: message}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap gives the cause to errors.Is and errors.As
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any AppError of the same code, so a wrapped copy still is its sentinel
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error caused by err
+func (e *AppError) Wrap(err error) *AppError {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithDetails returns a copy of the error carrying the given details
+func (e *AppError) WithDetails(details ...models.ErrorDetail) *AppError {
+	c := *e
+	c.Details = append(append([]models.ErrorDetail{}, e.Details...), details...)
+	return &c
+}
+
 var (
 	// ErrInternalServerError will throw if any the Internal Server Error happen
-	ErrInternalServerError = errors.New("Internal Server Error")
+	ErrInternalServerError = NewAppError("internal_error", http.StatusInternalServerError, "Internal Server Error")
 	// ErrNotFound will throw if the requested item is not exists
-	ErrNotFound = errors.New("Your requested Item is not found")
+	ErrNotFound = NewAppError("not_found", http.StatusNotFound, "Your requested Item is not found")
 	// ErrConflict will throw if the current action already exists
-	ErrConflict = errors.New("Your Item already exist")
+	ErrConflict = NewAppError("conflict", http.StatusConflict, "Your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
-	ErrBadParamInput = errors.New("Given Param is not valid")
+	ErrBadParamInput = NewAppError("bad_param_input", http.StatusBadRequest, "Given Param is not valid")
+	// ErrUnprocessableEntity will throw if the request body cannot be read
+	ErrUnprocessableEntity = NewAppError("unprocessable_entity", http.StatusUnprocessableEntity, "Request body cannot be processed")
+	// ErrInvalidCredential will throw if the given email and password do not match any user
+	ErrInvalidCredential = NewAppError("invalid_credential", http.StatusUnauthorized, "Invalid email or password")
+	// ErrUnauthorized will throw if the request carries no valid token
+	ErrUnauthorized = NewAppError("unauthorized", http.StatusUnauthorized, "Unauthorized")
+	// ErrForbidden will throw if the caller lacks the permission for the action
+	ErrForbidden = NewAppError("forbidden", http.StatusForbidden, "You are not allowed to perform this action")
+	// ErrVersionConflict will throw if the item was modified after the caller read it
+	ErrVersionConflict = NewAppError("version_conflict", http.StatusPreconditionFailed, "Your Item has been modified by another request")
+	// ErrPreconditionRequired will throw if a conditional request comes without its precondition
+	ErrPreconditionRequired = NewAppError("precondition_required", http.StatusPreconditionRequired, "Precondition is required")
+	// ErrServiceUnavailable will throw if a dependency is short-circuited by its breaker
+	ErrServiceUnavailable = NewAppError("service_unavailable", http.StatusServiceUnavailable, "Service is unavailable")
+	// ErrDependencyBusy will throw if a dependency already has as many calls in flight as its breaker allows
+	ErrDependencyBusy = NewAppError("dependency_busy", http.StatusServiceUnavailable, "Service is busy, retry later")
+	// ErrGatewayTimeout will throw if a dependency did not answer within its breaker timeout
+	ErrGatewayTimeout = NewAppError("gateway_timeout", http.StatusGatewayTimeout, "Service did not answer in time")
+	// ErrTooManyRequests will throw if the client spent its rate limit budget
+	ErrTooManyRequests = NewAppError("too_many_requests", http.StatusTooManyRequests, "Too many requests, retry later")
+	// ErrInvalidToken will throw if a mailed token is unknown, expired or already used
+	ErrInvalidToken = NewAppError("invalid_token", http.StatusBadRequest, "Token is invalid or expired")
+	// ErrEmailNotVerified will throw if a user logs in before verifying its email
+	ErrEmailNotVerified = NewAppError("email_not_verified", http.StatusForbidden, "Email address is not verified")
 )
 
 // GetStatusCode for handle status error
@@ -24,15 +95,9 @@ func GetStatusCode(err error) int {
 		return http.StatusOK
 	}
 
-	logrus.Error(err)
-	switch err {
-	case ErrInternalServerError:
-		return http.StatusInternalServerError
-	case ErrNotFound:
-		return http.StatusNotFound
-	case ErrConflict:
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Status
 	}
+	return http.StatusInternalServerError
 }
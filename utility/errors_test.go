@@ -0,0 +1,51 @@
+package utility_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kecci/goscription/models"
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusOK, utility.GetStatusCode(nil))
+	assert.Equal(t, http.StatusNotFound, utility.GetStatusCode(utility.ErrNotFound))
+	assert.Equal(t, http.StatusNotFound, utility.GetStatusCode(fmt.Errorf("fetching article: %w", utility.ErrNotFound)))
+	assert.Equal(t, http.StatusBadRequest, utility.GetStatusCode(utility.ErrBadParamInput.Wrap(errors.New("strconv"))))
+	assert.Equal(t, http.StatusInternalServerError, utility.GetStatusCode(errors.New("boom")))
+}
+
+func TestAppErrorIs(t *testing.T) {
+	cause := errors.New("no rows")
+	err := fmt.Errorf("repository: %w", utility.ErrNotFound.Wrap(cause))
+
+	assert.True(t, errors.Is(err, utility.ErrNotFound))
+	assert.True(t, errors.Is(err, cause))
+	assert.False(t, errors.Is(err, utility.ErrConflict))
+
+	var appErr *utility.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "not_found", appErr.Code)
+}
+
+func TestNewProblem(t *testing.T) {
+	err := utility.ErrBadParamInput.WithDetails(models.ErrorDetail{Field: "email", Message: "must be an email"})
+
+	assert.Equal(t, models.Problem{
+		Type:   "about:blank",
+		Title:  "Bad Request",
+		Status: http.StatusBadRequest,
+		Detail: "Given Param is not valid",
+		Code:   "bad_param_input",
+		Errors: []models.ErrorDetail{{Field: "email", Message: "must be an email"}},
+	}, utility.NewProblem(err))
+
+	// the cause of unknown errors never reaches the caller
+	problem := utility.NewProblem(errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "Internal Server Error", problem.Detail)
+}
@@ -0,0 +1,106 @@
+package utility
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchTerms splits a search query into its distinct lowercased words
+func SearchTerms(q string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// Highlight escapes text for HTML and wraps every occurrence of terms in <mark> tags
+func Highlight(text string, terms []string) string {
+	pattern := termsPattern(terms)
+	if pattern == nil {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString("</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// Snippet cuts a window of about width runes out of text around the first occurrence
+// of any of terms, on word boundaries, and highlights it. Cut ends are marked with an ellipsis.
+func Snippet(text string, terms []string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= width {
+		return Highlight(text, terms)
+	}
+
+	start := 0
+	if pattern := termsPattern(terms); pattern != nil {
+		if loc := pattern.FindStringIndex(text); loc != nil {
+			// keep a third of the window before the match
+			start = loc[0]
+			for i := 0; i < width/3 && start > 0; i++ {
+				_, size := utf8.DecodeLastRuneInString(text[:start])
+				start -= size
+			}
+		}
+	}
+	end := start
+	for i := 0; i < width && end < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	if start > 0 {
+		if i := strings.IndexByte(text[start:end], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+			end = start + i
+		}
+	}
+
+	snippet := Highlight(text[start:end], terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// termsPattern matches any of terms, case-insensitively, longest first
+func termsPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t != "" {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
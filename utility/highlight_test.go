@@ -0,0 +1,32 @@
+package utility_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"golang", "clean", "architecture"}, utility.SearchTerms("Golang, clean-architecture golang"))
+	assert.Empty(t, utility.SearchTerms(" ,; "))
+}
+
+func TestHighlight(t *testing.T) {
+	res := utility.Highlight("Go <b>Clean</b> Architecture", []string{"clean", "architecture"})
+	assert.Equal(t, "Go &lt;b&gt;<mark>Clean</mark>&lt;/b&gt; <mark>Architecture</mark>", res)
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 30) + "the golang keyword " + strings.Repeat("dolor sit ", 30)
+
+	res := utility.Snippet(text, []string{"golang"}, 60)
+
+	assert.Contains(t, res, "<mark>golang</mark>")
+	assert.True(t, strings.HasPrefix(res, "…"))
+	assert.True(t, strings.HasSuffix(res, "…"))
+	assert.True(t, len([]rune(res)) < 90)
+
+	assert.Equal(t, "short <mark>text</mark>", utility.Snippet("short\n text", []string{"text"}, 60))
+}
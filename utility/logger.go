@@ -0,0 +1,39 @@
+package utility
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loggerKey    contextKey = "logger"
+	requestIDKey contextKey = "request_id"
+)
+
+// WithRequestID returns a copy of ctx carrying the correlation id of the request
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the correlation id of the request, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// WithLogger returns a copy of ctx carrying the given logger
+func WithLogger(ctx context.Context, logger *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Logger returns the logger of ctx, so every line of a request carries its fields.
+// Outside of a request it falls back to the standard logger.
+func Logger(ctx context.Context) *logrus.Entry {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey).(*logrus.Entry); ok {
+			return logger
+		}
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
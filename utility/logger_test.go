@@ -0,0 +1,17 @@
+package utility_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kecci/goscription/utility"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	assert.Empty(t, utility.Logger(context.TODO()).Data)
+
+	ctx := utility.WithLogger(context.TODO(), logrus.WithField("request_id", "abc-123"))
+	assert.Equal(t, "abc-123", utility.Logger(ctx).Data["request_id"])
+}
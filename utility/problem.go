@@ -0,0 +1,75 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kecci/goscription/models"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 7807 responses
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// RenderError answers the request with the problem+json form of err. Errors that are
+// neither an AppError nor an echo.HTTPError are hidden behind ErrInternalServerError.
+func RenderError(c echo.Context, err error) error {
+	if c.Response().Committed {
+		return nil
+	}
+
+	problem := NewProblem(err)
+	problem.Instance = c.Request().URL.Path
+	if problem.Status >= http.StatusInternalServerError {
+		Logger(c.Request().Context()).Error(err)
+	} else {
+		Logger(c.Request().Context()).Debug(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(problem.Status)
+	}
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return c.JSON(problem.Status, problem)
+}
+
+// NewProblem builds the response body of err
+func NewProblem(err error) models.Problem {
+	appErr := ErrInternalServerError
+	var (
+		target  *AppError
+		httpErr *echo.HTTPError
+	)
+	switch {
+	case errors.As(err, &target):
+		appErr = target
+	case errors.As(err, &httpErr):
+		appErr = fromHTTPError(httpErr)
+	}
+
+	return models.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(appErr.Status),
+		Status: appErr.Status,
+		Detail: appErr.Message,
+		Code:   appErr.Code,
+		Errors: appErr.Details,
+	}
+}
+
+// fromHTTPError covers the errors raised by echo itself, like unknown routes
+func fromHTTPError(err *echo.HTTPError) *AppError {
+	text := http.StatusText(err.Code)
+	code := strings.ToLower(strings.Replace(text, " ", "_", -1))
+	if code == "" {
+		code = "http_error"
+	}
+
+	message := text
+	if err.Message != nil {
+		message = fmt.Sprint(err.Message)
+	}
+	return NewAppError(code, err.Code, message)
+}
@@ -0,0 +1,35 @@
+package utility
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of every span the application starts
+const TracerName = "github.com/kecci/goscription"
+
+// StartSpan starts a span as a child of the span of ctx, if any
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// EndSpan ends span, recording *err when it is set. Errors the caller is to blame for
+// are only tagged with their code, they do not mark the span as failed.
+func EndSpan(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		var appErr *AppError
+		if errors.As(*err, &appErr) && appErr.Status < http.StatusInternalServerError {
+			span.SetAttributes(attribute.String("error.code", appErr.Code))
+		} else {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,35 @@
+package utility_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kecci/goscription/utility"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEndSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	for _, err := range []error{nil, utility.ErrNotFound, errors.New("connection refused")} {
+		_, span := utility.StartSpan(context.TODO(), "ArticleService.GetByID")
+		utility.EndSpan(span, &err)
+	}
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, codes.Unset, spans[0].StatusCode)
+	assert.Equal(t, codes.Unset, spans[1].StatusCode)
+	assert.Contains(t, spans[1].Attributes, attribute.String("error.code", "not_found"))
+	assert.Equal(t, codes.Error, spans[2].StatusCode)
+	assert.Equal(t, "connection refused", spans[2].StatusMessage)
+}